@@ -1,16 +1,20 @@
 package main
 
 import (
-	"bytes"
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/kataras/golog"
 	"github.com/pkg/errors"
 )
 
 func buildContent() {
+	// `.output` commands only run in build, serve and lint use cached outputs
+	opts.runCommands = true
+
 	// helper functions
 	copy := func(path string) {
 		input, err := ioutil.ReadFile(filepath.Join(opts.contentBase, path))
@@ -64,7 +68,17 @@ func buildContent() {
 
 		// generate htmls for slide
 		if isSlide(path) {
-			content, err := getSlideHTML(path)
+			data, err := parseIndexSlide(path)
+			if err != nil {
+				return err
+			}
+
+			if data.Hidden() && !opts.includeDrafts {
+				golog.Infof("skip %s slide: %s", data.Status, path)
+				return nil
+			}
+
+			content, err := getSlideHTML(path, modifyPath(path))
 
 			if err != nil {
 				return errors.Wrapf(err, "could not render slide: %s", path)
@@ -72,11 +86,29 @@ func buildContent() {
 
 			write(modifyPath(path), content)
 
+			if data.Cover == siteURL(coverCardPath(path)) {
+				for _, card := range []string{coverCardPath(path), coverCardPNGPath(path)} {
+					if !isGeneratedCoverCard(card) {
+						continue
+					}
+
+					content, err := getCoverCard(path, strings.HasSuffix(card, ".png"))
+
+					if err != nil {
+						return err
+					}
+
+					write(card, content)
+				}
+			}
+
 			return nil
 		}
 
 		// for other files, just copy
-		copy(path)
+		if filepath.Base(path) != dirInfoFile {
+			copy(path)
+		}
 
 		return nil
 	}); err != nil {
@@ -108,13 +140,106 @@ func buildContent() {
 		slide.Path = modifyPath(slide.Path)
 	}
 
-	buf := &bytes.Buffer{}
-	if err := indexTemplate.Execute(buf, struct {
-		Index *indexData
-		All   []*slideData
-	}{data, allSlides}); err != nil {
+	c := newCatalog(allSlides)
+
+	content, err := renderIndex(&indexPage{
+		Index:   data,
+		Dir:     data,
+		All:     allSlides,
+		catalog: c,
+	})
+	if err != nil {
+		golog.Fatal(err)
+	}
+
+	write("index.html", content)
+
+	// paths of all index pages, for sitemap
+	pages := []string{""}
+
+	// generate tag, event and year pages
+	// a directory with the same path takes precedence
+	for _, l := range c.all() {
+		if data.findDir(l.Path) != nil || hasIndexHTML(l.Path) {
+			continue
+		}
+
+		pages = append(pages, l.Path)
+
+		title, _ := c.find(l.Path)
+
+		content, err := renderIndex(&indexPage{
+			Title:   title,
+			Index:   data,
+			All:     l.Slides,
+			catalog: c,
+		})
+		if err != nil {
+			golog.Fatal(err)
+		}
+
+		mkdir(l.Path)
+		write(filepath.Join(l.Path, "index.html"), content)
+	}
+
+	// generate search index and page
+	decks, err := buildSearchIndex(allSlides)
+	if err != nil {
+		golog.Fatal(err)
+	}
+
+	index, err := json.Marshal(decks)
+	if err != nil {
+		golog.Fatal(err)
+	}
+
+	write("search.json", index)
+
+	content, err = getSearchHTML(siteURL("search.json"))
+	if err != nil {
 		golog.Fatal(err)
 	}
 
-	write("index.html", buf.Bytes())
+	write("search.html", content)
+
+	// generate directory pages, an index.html of the content is kept
+	data.walk(func(id *indexData) {
+		if id == data || !id.HasSlides() || hasIndexHTML(id.Path) {
+			return
+		}
+
+		content, err := renderIndex(newDirPage(data, data.findDir(id.Path)))
+		if err != nil {
+			golog.Fatal(err)
+		}
+
+		write(filepath.Join(id.Path, "index.html"), content)
+		pages = append(pages, id.Path)
+	})
+
+	// generate feeds and sitemap
+	if opts.baseURL == "" {
+		golog.Warn("base url is not provided, skip generating feeds and sitemap")
+	} else {
+		for _, f := range []struct {
+			path string
+			gen  func() ([]byte, error)
+		}{
+			{"atom.xml", func() ([]byte, error) { return getAtomFeed(allSlides) }},
+			{"rss.xml", func() ([]byte, error) { return getRSSFeed(allSlides) }},
+			{"sitemap.xml", func() ([]byte, error) { return getSitemap(allSlides, pages) }},
+		} {
+			content, err := f.gen()
+			if err != nil {
+				golog.Fatal(err)
+			}
+
+			write(f.path, content)
+		}
+	}
+
+	// write resized images, after all slides are parsed
+	if err := writeImageVariants(); err != nil {
+		golog.Fatal(err)
+	}
 }
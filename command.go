@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kataras/golog"
+	"github.com/pkg/errors"
+)
+
+const commandTimeout = 5 * time.Minute
+
+// commandPlaceholder is shown by serve and lint for outputs not built yet
+const commandPlaceholder = "# output is generated by `mypresent build`"
+
+// characters of shell operators and expansions, commands are run without
+// a shell, so they are rejected instead of silently passed as arguments
+const shellChars = ";&|<>$`(){}\n"
+
+// splitCommand splits a command into arguments, single and double quotes
+// and backslash escapes work like in sh
+func splitCommand(command string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	quote := rune(0)
+	escaped := false
+
+	for _, r := range command {
+		switch {
+		case escaped:
+			// in double quotes, a backslash only escapes these characters
+			if quote == '"' && !strings.ContainsRune("$`\"\\\n", r) {
+				arg.WriteRune('\\')
+			}
+			arg.WriteRune(r)
+			escaped = false
+
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\':
+				escaped = true
+			case r == '$' || r == '`':
+				return nil, errors.Errorf("shell expansion is not supported: %s", command)
+			default:
+				arg.WriteRune(r)
+			}
+
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+
+		case r == '\\':
+			escaped = true
+			inArg = true
+
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+
+		case strings.ContainsRune(shellChars, r):
+			return nil, errors.Errorf("shell operators are not supported: %s", command)
+
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, errors.Errorf("unterminated quote or escape: %s", command)
+	}
+
+	if inArg {
+		args = append(args, arg.String())
+	}
+
+	return args, nil
+}
+
+// commandAllowed reports whether the command is allowed by `--allow-command`,
+// an allowed entry matches the leading arguments of the command
+func commandAllowed(command string) bool {
+	args, err := splitCommand(command)
+	if err != nil || len(args) == 0 {
+		return false
+	}
+
+	for _, allowed := range opts.allowedCommands {
+		prefix, err := splitCommand(allowed)
+		if err != nil || len(prefix) == 0 || len(prefix) > len(args) {
+			continue
+		}
+
+		match := true
+		for i := range prefix {
+			if prefix[i] != args[i] {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			return true
+		}
+	}
+
+	return false
+}
+
+// runCommand implements present.Context.RunCommand
+// outputs are cached in `opts.cacheDir` by the hash of the command and
+// its input files, commands only run in build, serve and lint use the
+// cached outputs, only commands which run are checked by `commandAllowed`
+func runCommand(dir, command string, inputs []string) ([]byte, error) {
+	key, err := commandKey(dir, command, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := filepath.Join(opts.cacheDir, "output", key)
+	if buf, err := ioutil.ReadFile(cached); err == nil {
+		return buf, nil
+	}
+
+	if !opts.runCommands {
+		return []byte(commandPlaceholder), nil
+	}
+
+	if !commandAllowed(command) {
+		return nil, errors.Errorf("command is not allowed, use --allow-command to allow it: %s", command)
+	}
+
+	args, err := splitCommand(command)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	golog.Infof("run command in %s: %s", dir, command)
+
+	out, err := cmd.Output()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, errors.Errorf("command timed out after %v: %s", commandTimeout, command)
+	}
+
+	if err != nil {
+		// a failing command, e.g. a failing test, is still a valid output
+		if _, ok := err.(*exec.ExitError); !ok {
+			return nil, errors.Wrapf(err, "could not run command: %s", command)
+		}
+
+		golog.Warnf("command %s: %v\n%s", command, err, stderr.String())
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cached), 0755); err != nil {
+		return nil, errors.Wrap(err, "could not create cache dir")
+	}
+
+	if err := ioutil.WriteFile(cached, out, 0644); err != nil {
+		return nil, errors.Wrap(err, "could not write cache")
+	}
+
+	return out, nil
+}
+
+// files which are not default inputs of commands, slides change with every
+// edit of the text and media files are slow to hash
+var skipInputExts = map[string]bool{
+	".slide": true,
+	".png":   true,
+	".jpg":   true,
+	".jpeg":  true,
+	".gif":   true,
+	".svg":   true,
+	".webp":  true,
+	".ico":   true,
+	".mp4":   true,
+	".webm":  true,
+	".mov":   true,
+	".cast":  true,
+	".pdf":   true,
+}
+
+// defaultInputs returns the files under dir, without hidden dirs,
+// node_modules, the build output, slides and media files
+func defaultInputs(dir string) ([]string, error) {
+	output := ""
+	if opts.output != "" {
+		output, _ = filepath.Abs(opts.output)
+	}
+
+	var files []string
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if p == dir {
+				return nil
+			}
+
+			if abs, _ := filepath.Abs(p); strings.HasPrefix(info.Name(), ".") || info.Name() == "node_modules" || abs == output {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if info.Mode().IsRegular() && !skipInputExts[strings.ToLower(filepath.Ext(p))] {
+			files = append(files, p)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// commandKey returns the hash of the command and the content of its inputs,
+// inputs are globs relative to dir, `defaultInputs` if empty
+func commandKey(dir, command string, inputs []string) (string, error) {
+	var files []string
+
+	if len(inputs) == 0 {
+		var err error
+		if files, err = defaultInputs(dir); err != nil {
+			return "", errors.Wrap(err, "could not list inputs")
+		}
+	}
+
+	for _, input := range inputs {
+		matches, err := filepath.Glob(filepath.Join(dir, input))
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid input: %s", input)
+		}
+
+		if len(matches) == 0 {
+			return "", errors.Errorf("input does not exist: %s", input)
+		}
+
+		files = append(files, matches...)
+	}
+
+	sort.Strings(files)
+
+	h := sha256.New()
+	io.WriteString(h, command)
+
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return "", errors.Wrap(err, "could not read input")
+		}
+
+		fh := sha256.New()
+		_, err = io.Copy(fh, f)
+		f.Close()
+
+		if err != nil {
+			return "", errors.Wrap(err, "could not read input")
+		}
+
+		rel, _ := filepath.Rel(dir, file)
+		io.WriteString(h, "\x00"+filepath.ToSlash(rel)+"\x00"+hex.EncodeToString(fh.Sum(nil)))
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
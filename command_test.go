@@ -0,0 +1,115 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCommandAllowed(t *testing.T) {
+	defer func(allowed []string) { opts.allowedCommands = allowed }(opts.allowedCommands)
+	opts.allowedCommands = []string{"echo", "go test", "printf '%s\\n'"}
+
+	tests := []struct {
+		command string
+		allowed bool
+	}{
+		{"echo", true},
+		{"echo hi", true},
+		{"  go   test  ./...", true},
+		{`printf '%s\n' a b`, true},
+		{"go vet ./...", false},
+		{"gotest", false},
+		{"echoo hi", false},
+		{"echo hi; touch /tmp/pwned", false},
+		{"go test && rm -rf ~", false},
+		{"echo hi | sh", false},
+		{"go test $(curl example.com)", false},
+		{"go test `curl example.com`", false},
+		{`echo "$(id)"`, false},
+		{"echo hi > /tmp/pwned", false},
+		{"echo 'unterminated", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := commandAllowed(tt.command); got != tt.allowed {
+			t.Errorf("commandAllowed(%q) = %v; want %v", tt.command, got, tt.allowed)
+		}
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		args    []string
+	}{
+		{"go test ./...", []string{"go", "test", "./..."}},
+		{`printf '%s; %s\n' "a b" c\ d`, []string{"printf", `%s; %s\n`, "a b", "c d"}},
+		{`echo "" ''`, []string{"echo", "", ""}},
+		{`printf "a\tb\"\\"`, []string{"printf", `a\tb"\`}},
+	}
+
+	for _, tt := range tests {
+		args, err := splitCommand(tt.command)
+		if err != nil {
+			t.Errorf("splitCommand(%q): %v", tt.command, err)
+		} else if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("splitCommand(%q) = %q; want %q", tt.command, args, tt.args)
+		}
+	}
+}
+
+func TestRunCommandCached(t *testing.T) {
+	defer func(cache string, allowed []string, run bool) {
+		opts.cacheDir, opts.allowedCommands, opts.runCommands = cache, allowed, run
+	}(opts.cacheDir, opts.allowedCommands, opts.runCommands)
+
+	// serve and lint, without --allow-command
+	opts.cacheDir = t.TempDir()
+	opts.allowedCommands = nil
+	opts.runCommands = false
+
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := commandKey(dir, "go test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached := filepath.Join(opts.cacheDir, "output", key)
+	if err := os.MkdirAll(filepath.Dir(cached), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(cached, []byte("ok\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		command string
+		out     string
+	}{
+		{"go test", "ok\n"},
+		{"go vet", commandPlaceholder},
+	}
+
+	for _, tt := range tests {
+		out, err := runCommand(dir, tt.command, nil)
+		if err != nil {
+			t.Errorf("%s: %v", tt.command, err)
+		} else if string(out) != tt.out {
+			t.Errorf("%s: got %q; want %q", tt.command, out, tt.out)
+		}
+	}
+
+	// build only runs allowed commands
+	opts.runCommands = true
+	if _, err := runCommand(dir, "go vet", nil); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("got error %v; want not allowed", err)
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"strings"
+
+	"github.com/cj1128/mypresent/present"
+	"github.com/pkg/errors"
+)
+
+// theme colors for slides without `.theme`, picked by the hash of the title
+var coverCardColors = []string{
+	"#3f51b5", "#00897b", "#e53935", "#8e24aa",
+	"#f4511e", "#1e88e5", "#43a047", "#6d4c41",
+}
+
+const (
+	coverCardWidth  = 1200
+	coverCardHeight = 630
+
+	// maximum characters of a title line, text is centered and kept narrow
+	// so it still fits when index pages crop the card to a square
+	coverCardLineLength = 16
+)
+
+// coverCardPath returns the path of the generated cover card of the slide,
+// e.g. `2019/talk.slide` -> `2019/talk.cover.svg`
+// fp is relative to `contentBase`
+func coverCardPath(fp string) string {
+	return strings.TrimPrefix(strings.TrimSuffix(fp, ".slide"), "/") + ".cover.svg"
+}
+
+// coverCardPNGPath returns the path of the png version of the cover card,
+// e.g. `2019/talk.slide` -> `2019/talk.cover.png`, used as `og:image`
+// since svg images are not shown by social sites
+func coverCardPNGPath(fp string) string {
+	return strings.TrimSuffix(coverCardPath(fp), ".svg") + ".png"
+}
+
+func isCoverCard(path string) bool {
+	return strings.HasSuffix(path, ".cover.svg") || strings.HasSuffix(path, ".cover.png")
+}
+
+// isGeneratedCoverCard reports whether path is a cover card which is
+// generated from its slide, a cover card file in `contentBase` is kept as is
+// path is relative to `contentBase`
+func isGeneratedCoverCard(path string) bool {
+	return isCoverCard(path) &&
+		!fileExists(filepath.Join(opts.contentBase, path)) &&
+		fileExists(filepath.Join(opts.contentBase, slidePathOfCoverCard(path)))
+}
+
+// slidePathOfCoverCard is the reverse of coverCardPath and coverCardPNGPath
+func slidePathOfCoverCard(path string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(path, ".cover.svg"), ".cover.png") + ".slide"
+}
+
+// shareImage returns the url of the png cover card if cover is a
+// generated svg cover card, otherwise cover itself
+func shareImage(cover string) string {
+	if strings.HasSuffix(cover, ".cover.svg") {
+		return strings.TrimSuffix(cover, ".svg") + ".png"
+	}
+
+	return cover
+}
+
+// fp is relative to `contentBase`
+// the svg card is returned, or the png card if png is true
+func getCoverCard(fp string, png bool) ([]byte, error) {
+	doc, err := parseSlide(fp, present.TitlesOnly)
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse slide: %s", fp)
+	}
+
+	if png {
+		return renderCoverCardPNG(doc)
+	}
+
+	return renderCoverCard(doc), nil
+}
+
+// coverCardText is a line of text on the cover card
+type coverCardText struct {
+	Text    string
+	Y       int // baseline
+	Size    int
+	Bold    bool
+	Opacity float64
+}
+
+// layoutCoverCard returns the background color and the centered lines of
+// the title, subtitle and date of the document
+func layoutCoverCard(doc *present.Doc) (string, []coverCardText) {
+	color := doc.Theme
+	if color == "" {
+		h := fnv.New32a()
+		h.Write([]byte(doc.Title))
+		color = coverCardColors[h.Sum32()%uint32(len(coverCardColors))]
+	}
+
+	var result []coverCardText
+
+	title := wrapText(doc.Title, coverCardLineLength, 4)
+	y := coverCardHeight/2 - (len(title)-1)*40
+
+	for _, line := range title {
+		result = append(result, coverCardText{line, y, 64, true, 1})
+		y += 80
+	}
+
+	// the subtitle is half the size of the title, a line takes the same width
+	// only one line fits above the date below a title of 4 lines
+	subtitleLines := 2
+	if len(title) == 4 {
+		subtitleLines = 1
+	}
+
+	for _, line := range wrapText(doc.Subtitle, coverCardLineLength*2, subtitleLines) {
+		result = append(result, coverCardText{line, y, 32, false, 0.85})
+		y += 44
+	}
+
+	if !doc.Time.IsZero() {
+		result = append(result, coverCardText{doc.Time.Format("2 January 2006"), coverCardHeight - 60, 28, false, 0.7})
+	}
+
+	return color, result
+}
+
+// renderCoverCard renders an svg card from the title, subtitle, date
+// and theme color of the document
+func renderCoverCard(doc *present.Doc) []byte {
+	color, texts := layoutCoverCard(doc)
+
+	buf := &bytes.Buffer{}
+	escape := func(s string) string {
+		b := &bytes.Buffer{}
+		xml.EscapeText(b, []byte(s))
+		return b.String()
+	}
+
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		coverCardWidth, coverCardHeight, coverCardWidth, coverCardHeight)
+	fmt.Fprintf(buf, `<rect width="100%%" height="100%%" fill="%s"/>`, escape(color))
+	buf.WriteString(`<g fill="#fff" font-family="Roboto, Helvetica, Arial, sans-serif" text-anchor="middle">`)
+
+	for _, t := range texts {
+		fmt.Fprintf(buf, `<text x="%d" y="%d" font-size="%d"`, coverCardWidth/2, t.Y, t.Size)
+		if t.Bold {
+			buf.WriteString(` font-weight="bold"`)
+		}
+		if t.Opacity != 1 {
+			fmt.Fprintf(buf, ` opacity="%g"`, t.Opacity)
+		}
+		fmt.Fprintf(buf, `>%s</text>`, escape(t.Text))
+	}
+
+	buf.WriteString(`</g></svg>`)
+
+	return buf.Bytes()
+}
+
+// wrapText splits s into lines of at most n columns, at most maxLines
+// lines, the last line ends with `…` if s is cut
+// lines break at spaces, or between wide characters like Chinese, which
+// take two columns, words longer than a line are broken anywhere
+func wrapText(s string, n, maxLines int) []string {
+	type token struct {
+		text  string
+		width int
+		space bool // separated from the previous token by a space
+	}
+
+	var tokens []token
+
+	for i, word := range strings.Fields(s) {
+		space := i > 0
+		var run []rune
+		width := 0
+
+		flush := func() {
+			if len(run) > 0 {
+				tokens = append(tokens, token{string(run), width, space})
+				space = false
+			}
+			run, width = nil, 0
+		}
+
+		for _, r := range word {
+			w := runeWidth(r)
+			if w == 2 || width+w > n {
+				flush()
+			}
+
+			run = append(run, r)
+			width += w
+
+			if w == 2 {
+				flush()
+			}
+		}
+
+		flush()
+	}
+
+	var lines []string
+	var line string
+	width := 0
+
+	for _, t := range tokens {
+		w := t.width
+		if line != "" && t.space {
+			w++
+		}
+
+		if line != "" && width+w > n {
+			lines = append(lines, line)
+			line, width, w = "", 0, t.width
+		}
+
+		if line != "" && t.space {
+			line += " "
+		}
+		line += t.text
+		width += w
+	}
+
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lines[maxLines-1] += "…"
+	}
+
+	return lines
+}
+
+// runeWidth returns 2 for east asian wide characters, otherwise 1
+func runeWidth(r rune) int {
+	switch {
+	case r >= 0x1100 && r <= 0x115f,
+		r >= 0x2e80 && r <= 0xa4cf,
+		r >= 0xac00 && r <= 0xd7a3,
+		r >= 0xf900 && r <= 0xfaff,
+		r >= 0xfe30 && r <= 0xfe4f,
+		r >= 0xff00 && r <= 0xff60,
+		r >= 0xffe0 && r <= 0xffe6,
+		r >= 0x20000 && r <= 0x3fffd:
+		return 2
+	}
+
+	return 1
+}
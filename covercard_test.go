@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cj1128/mypresent/present"
+)
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		in       string
+		n, max   int
+		expected []string
+	}{
+		{"", 16, 4, nil},
+		{"Go Concurrency Patterns", 16, 4, []string{"Go Concurrency", "Patterns"}},
+		{"  Go   Concurrency  ", 16, 4, []string{"Go Concurrency"}},
+		{"a b c d e f g h", 3, 2, []string{"a b", "c d…"}},
+		// words longer than a line
+		{"Supercalifragilistic", 8, 4, []string{"Supercal", "ifragili", "stic"}},
+		// wide characters take two columns and break anywhere
+		{"深入理解Go语言的并发编程模型", 16, 4, []string{"深入理解Go语言的", "并发编程模型"}},
+		{"Go 语言并发", 8, 4, []string{"Go 语言", "并发"}},
+		{"一二三四五六七八九十一二三四五六七八九十", 8, 2, []string{"一二三四", "五六七八…"}},
+	}
+
+	for _, tt := range tests {
+		got := wrapText(tt.in, tt.n, tt.max)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("wrapText(%q, %d, %d) = %q; want %q", tt.in, tt.n, tt.max, got, tt.expected)
+		}
+	}
+}
+
+func TestLayoutCoverCard(t *testing.T) {
+	doc := &present.Doc{
+		Title:    "并发编程模型与实践：从入门到精通",
+		Subtitle: strings.Repeat("a subtitle which is way too long ", 10),
+		Theme:    "#123456",
+	}
+
+	color, texts := layoutCoverCard(doc)
+	if color != "#123456" {
+		t.Errorf("got color %q", color)
+	}
+
+	var title, subtitle int
+	for _, text := range texts {
+		if text.Size == 64 {
+			title++
+			if w := textWidth(text.Text); w > coverCardLineLength+1 {
+				t.Errorf("title line %q is %d columns", text.Text, w)
+			}
+		} else {
+			subtitle++
+			if w := textWidth(text.Text); w > coverCardLineLength*2+1 {
+				t.Errorf("subtitle line %q is %d columns", text.Text, w)
+			}
+		}
+	}
+
+	if title != 2 || subtitle != 2 {
+		t.Errorf("got %d title and %d subtitle lines; want 2 and 2", title, subtitle)
+	}
+}
+
+func textWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+func TestRenderCoverCardPNG(t *testing.T) {
+	buf, err := renderCoverCardPNG(&present.Doc{Title: "Hello 世界", Subtitle: "sub", Theme: "#3f51b5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if size := img.Bounds().Size(); size != image.Pt(coverCardWidth, coverCardHeight) {
+		t.Errorf("got size %v", size)
+	}
+
+	if c := color.RGBAModel.Convert(img.At(0, 0)); c != (color.RGBA{0x3f, 0x51, 0xb5, 255}) {
+		t.Errorf("got background %v", c)
+	}
+
+	// some text is drawn in the middle
+	white := false
+	for x := 0; x < coverCardWidth && !white; x++ {
+		for y := coverCardHeight/2 - 60; y < coverCardHeight/2; y++ {
+			if r, _, _, _ := img.At(x, y).RGBA(); r > 0xf000 {
+				white = true
+				break
+			}
+		}
+	}
+	if !white {
+		t.Error("no text is drawn")
+	}
+}
+
+func TestCoverCardPaths(t *testing.T) {
+	if got := coverCardPNGPath("2019/talk.slide"); got != "2019/talk.cover.png" {
+		t.Errorf("coverCardPNGPath = %q", got)
+	}
+
+	for _, p := range []string{"2019/talk.cover.svg", "2019/talk.cover.png"} {
+		if !isCoverCard(p) {
+			t.Errorf("isCoverCard(%q) = false", p)
+		}
+		if got := slidePathOfCoverCard(p); got != "2019/talk.slide" {
+			t.Errorf("slidePathOfCoverCard(%q) = %q", p, got)
+		}
+	}
+
+	for in, out := range map[string]string{
+		"/talks/a.cover.svg":  "/talks/a.cover.png",
+		"/talks/img/a.svg":    "/talks/img/a.svg",
+		"https://x.com/a.png": "https://x.com/a.png",
+	} {
+		if got := shareImage(in); got != out {
+			t.Errorf("shareImage(%q) = %q; want %q", in, got, out)
+		}
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	for in, out := range map[string]color.Color{
+		"#fff":    color.RGBA{255, 255, 255, 255},
+		"#3f51b5": color.RGBA{0x3f, 0x51, 0xb5, 255},
+		"#12345":  nil,
+		"red":     nil,
+	} {
+		c, err := parseHexColor(in)
+		if out == nil {
+			if err == nil {
+				t.Errorf("parseHexColor(%q): expected error", in)
+			}
+		} else if c != out {
+			t.Errorf("parseHexColor(%q) = %v; want %v", in, c, out)
+		}
+	}
+}
+
+func TestIsGeneratedCoverCard(t *testing.T) {
+	writeContent(t, map[string]string{
+		"2019/talk.slide":     "Talk\n",
+		"2019/talk.cover.png": "png",
+		"2019/other.slide":    "Other\n",
+	})
+
+	for path, want := range map[string]bool{
+		"2019/talk.cover.svg":    true,
+		"/2019/talk.cover.svg":   true,
+		"2019/talk.cover.png":    false,
+		"2019/other.cover.png":   true,
+		"2019/missing.cover.svg": false,
+		"2019/talk.slide":        false,
+	} {
+		if got := isGeneratedCoverCard(path); got != want {
+			t.Errorf("isGeneratedCoverCard(%q) = %v; want %v", path, got, want)
+		}
+	}
+}
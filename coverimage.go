@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cj1128/mypresent/present"
+	"github.com/pkg/errors"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// fonts of png cover cards, `opts.coverFont` is tried first, the go fonts
+// are used for characters it doesn't have
+var coverFonts struct {
+	once    sync.Once
+	err     error
+	custom  *opentype.Font
+	regular *opentype.Font
+	bold    *opentype.Font
+}
+
+func loadCoverFonts() error {
+	coverFonts.once.Do(func() {
+		var err error
+
+		if coverFonts.regular, err = opentype.Parse(goregular.TTF); err != nil {
+			coverFonts.err = err
+			return
+		}
+
+		if coverFonts.bold, err = opentype.Parse(gobold.TTF); err != nil {
+			coverFonts.err = err
+			return
+		}
+
+		if opts.coverFont == "" {
+			return
+		}
+
+		buf, err := ioutil.ReadFile(opts.coverFont)
+		if err != nil {
+			coverFonts.err = errors.Wrap(err, "could not read cover font")
+			return
+		}
+
+		// a font collection (.ttc) uses its first font
+		c, err := opentype.ParseCollection(buf)
+		if err == nil {
+			coverFonts.custom, err = c.Font(0)
+		}
+		if err != nil {
+			coverFonts.err = errors.Wrapf(err, "could not parse cover font: %s", opts.coverFont)
+		}
+	})
+
+	return coverFonts.err
+}
+
+// coverFaces returns the faces to draw text of the size, in order of
+// preference
+func coverFaces(size int, bold bool) ([]font.Face, error) {
+	var result []font.Face
+
+	fonts := []*opentype.Font{coverFonts.custom, coverFonts.regular}
+	if bold {
+		fonts[1] = coverFonts.bold
+	}
+
+	for _, f := range fonts {
+		if f == nil {
+			continue
+		}
+
+		face, err := opentype.NewFace(f, &opentype.FaceOptions{
+			Size:    float64(size),
+			DPI:     72,
+			Hinting: font.HintingFull,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create font face")
+		}
+
+		result = append(result, face)
+	}
+
+	return result, nil
+}
+
+// faceOf returns the first face which has a glyph for r, or the last face
+func faceOf(faces []font.Face, r rune) (font.Face, fixed.Int26_6) {
+	for _, face := range faces {
+		if advance, ok := face.GlyphAdvance(r); ok {
+			return face, advance
+		}
+	}
+
+	face := faces[len(faces)-1]
+	advance, _ := face.GlyphAdvance(r)
+
+	return face, advance
+}
+
+// renderCoverCardPNG renders the cover card of `renderCoverCard` as png
+func renderCoverCardPNG(doc *present.Doc) ([]byte, error) {
+	if err := loadCoverFonts(); err != nil {
+		return nil, err
+	}
+
+	bg, texts := layoutCoverCard(doc)
+
+	c, err := parseHexColor(bg)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, coverCardWidth, coverCardHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+
+	for _, t := range texts {
+		faces, err := coverFaces(t.Size, t.Bold)
+		if err != nil {
+			return nil, err
+		}
+
+		var width fixed.Int26_6
+		for _, r := range t.Text {
+			_, advance := faceOf(faces, r)
+			width += advance
+		}
+
+		src := image.NewUniform(color.NRGBA{255, 255, 255, uint8(t.Opacity * 255)})
+		dot := fixed.Point26_6{
+			X: fixed.I(coverCardWidth/2) - width/2,
+			Y: fixed.I(t.Y),
+		}
+
+		for _, r := range t.Text {
+			face, advance := faceOf(faces, r)
+
+			if dr, mask, maskp, _, ok := face.Glyph(dot, r); ok {
+				draw.DrawMask(img, dr, src, image.Point{}, mask, maskp, draw.Over)
+			}
+
+			dot.X += advance
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		return nil, errors.Wrap(err, "could not encode cover card")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// parseHexColor parses `#rgb` or `#rrggbb`
+func parseHexColor(s string) (color.Color, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return nil, errors.Errorf("invalid color: %q", s)
+	}
+
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}, nil
+}
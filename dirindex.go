@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// name of the optional metadata file in each content directory
+//
+// format:
+//
+//	title
+//	[.order newest|oldest|name]
+//	[description lines]
+const dirInfoFile = "index.info"
+
+type dirInfo struct {
+	Title       string
+	Description string
+	Order       string
+}
+
+// title of the site if the top level directory has no title
+const defaultSiteTitle = "CJ's Slides"
+
+// siteTitle returns the title of the top level `index.info`
+func siteTitle() string {
+	info, err := readDirInfo(".")
+	if err != nil || info.Title == "" {
+		return defaultSiteTitle
+	}
+
+	return info.Title
+}
+
+// dir is relative to `contentBase`
+// a missing file is not an error
+func readDirInfo(dir string) (*dirInfo, error) {
+	result := &dirInfo{}
+
+	p := path.Join(opts.contentBase, dir, dirInfoFile)
+	if !fileExists(p) {
+		return result, nil
+	}
+
+	buf, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read dir info: %s", dir)
+	}
+
+	var desc []string
+	s := bufio.NewScanner(bytes.NewReader(buf))
+	for s.Scan() {
+		text := strings.TrimSpace(s.Text())
+
+		switch {
+		case text == "" && result.Title == "":
+			continue
+
+		case result.Title == "":
+			result.Title = text
+
+		case strings.HasPrefix(text, ".order "):
+			result.Order = strings.TrimSpace(text[len(".order "):])
+
+			switch result.Order {
+			case "newest", "oldest", "name":
+			default:
+				return nil, errors.Errorf("%s: unknown order %q", p, result.Order)
+			}
+
+		default:
+			desc = append(desc, text)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, errors.Wrapf(err, "could not read dir info: %s", dir)
+	}
+
+	result.Description = strings.TrimSpace(strings.Join(desc, "\n"))
+
+	return result, nil
+}
+
+// sortSlides sorts slides of the directory according to its order,
+// default is newest first
+func (id *indexData) sortSlides() {
+	sort.SliceStable(id.Slides, func(i, j int) bool {
+		a, b := id.Slides[i], id.Slides[j]
+
+		switch id.Order {
+		case "oldest":
+			return a.Time.Before(b.Time)
+		case "name":
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		default:
+			return a.Time.After(b.Time)
+		}
+	})
+
+	sort.SliceStable(id.Children, func(i, j int) bool {
+		return strings.ToLower(id.Children[i].Title) < strings.ToLower(id.Children[j].Title)
+	})
+}
+
+// findDir returns the chain of directories from the top level to dir,
+// nil if dir is not found
+func (id *indexData) findDir(dir string) []*indexData {
+	dir = strings.Trim(dir, "/")
+	chain := []*indexData{id}
+
+	if dir == "" || dir == "." {
+		return chain
+	}
+
+	current := id
+
+Parts:
+	for _, name := range strings.Split(dir, "/") {
+		for _, c := range current.Children {
+			if c.Name == name {
+				current = c
+				chain = append(chain, c)
+				continue Parts
+			}
+		}
+
+		return nil
+	}
+
+	return chain
+}
+
+// hasIndexHTML reports whether the content has its own index.html in dir,
+// which is served instead of the generated page
+func hasIndexHTML(dir string) bool {
+	return fileExists(filepath.Join(opts.contentBase, filepath.FromSlash(dir), "index.html"))
+}
+
+// walk calls fn for every directory in the tree, including id itself
+func (id *indexData) walk(fn func(*indexData)) {
+	fn(id)
+
+	for _, c := range id.Children {
+		c.walk(fn)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeContent creates files under a temporary `opts.contentBase`
+func writeContent(t *testing.T, files map[string]string) {
+	base := opts.contentBase
+	t.Cleanup(func() { opts.contentBase = base })
+
+	opts.contentBase = t.TempDir()
+
+	for name, content := range files {
+		p := filepath.Join(opts.contentBase, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadDirInfo(t *testing.T) {
+	writeContent(t, map[string]string{
+		"index.info":       "\nMy Talks\n.order oldest\nAll my talks.\nSince 2015.\n",
+		"go/index.info":    "Go\n",
+		"bad/index.info":   "Bad\n.order random\n",
+		"empty/index.info": "",
+	})
+
+	tests := []struct {
+		dir  string
+		info *dirInfo
+		err  string
+	}{
+		{".", &dirInfo{"My Talks", "All my talks.\nSince 2015.", "oldest"}, ""},
+		{"go", &dirInfo{Title: "Go"}, ""},
+		{"empty", &dirInfo{}, ""},
+		{"missing", &dirInfo{}, ""},
+		{"bad", nil, `unknown order "random"`},
+	}
+
+	for _, tt := range tests {
+		info, err := readDirInfo(tt.dir)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: got error %v; want %q", tt.dir, err, tt.err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %v", tt.dir, err)
+		} else if !reflect.DeepEqual(info, tt.info) {
+			t.Errorf("%s: got %+v; want %+v", tt.dir, info, tt.info)
+		}
+	}
+
+	if got := siteTitle(); got != "My Talks" {
+		t.Errorf("siteTitle() = %q; want %q", got, "My Talks")
+	}
+}
+
+func TestSiteTitleDefault(t *testing.T) {
+	writeContent(t, nil)
+
+	if got := siteTitle(); got != defaultSiteTitle {
+		t.Errorf("siteTitle() = %q; want %q", got, defaultSiteTitle)
+	}
+}
+
+func TestFindDir(t *testing.T) {
+	b := &indexData{Name: "b", Path: "a/b/"}
+	a := &indexData{Name: "a", Path: "a/", Children: []*indexData{b}}
+	root := &indexData{Name: ".", Children: []*indexData{a}}
+
+	tests := []struct {
+		dir   string
+		chain []*indexData
+	}{
+		{"", []*indexData{root}},
+		{"/", []*indexData{root}},
+		{"a", []*indexData{root, a}},
+		{"/a/b/", []*indexData{root, a, b}},
+		{"a/c/", nil},
+		{"b/", nil},
+	}
+
+	for _, tt := range tests {
+		if got := root.findDir(tt.dir); !reflect.DeepEqual(got, tt.chain) {
+			t.Errorf("findDir(%q) returned %d dirs; want %d", tt.dir, len(got), len(tt.chain))
+		}
+	}
+}
+
+func TestSortSlides(t *testing.T) {
+	date := func(year int) time.Time {
+		return time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		order string
+		names []string
+	}{
+		{"", []string{"b", "C", "a"}},
+		{"newest", []string{"b", "C", "a"}},
+		{"oldest", []string{"a", "C", "b"}},
+		{"name", []string{"a", "b", "C"}},
+	}
+
+	for _, tt := range tests {
+		id := &indexData{
+			Slides: []*slideData{
+				{Name: "a", Time: date(2018)},
+				{Name: "b", Time: date(2020)},
+				{Name: "C", Time: date(2019)},
+			},
+			dirInfo: &dirInfo{Order: tt.order},
+		}
+		id.sortSlides()
+
+		var names []string
+		for _, s := range id.Slides {
+			names = append(names, s.Name)
+		}
+
+		if !reflect.DeepEqual(names, tt.names) {
+			t.Errorf("order %q: got %v; want %v", tt.order, names, tt.names)
+		}
+	}
+}
+
+func TestHasIndexHTML(t *testing.T) {
+	writeContent(t, map[string]string{
+		"custom/index.html": "<p>hi</p>",
+		"custom/a.slide":    "A\n",
+		"plain/a.slide":     "A\n",
+	})
+
+	for dir, want := range map[string]bool{
+		"custom/": true,
+		"/custom": true,
+		"plain/":  false,
+	} {
+		if got := hasIndexHTML(dir); got != want {
+			t.Errorf("hasIndexHTML(%q) = %v; want %v", dir, got, want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/xml"
+	"mime"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// number of slides in the feeds
+const maxFeedEntries = 20
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Links   []atomLink  `xml:"link"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"` // default author of entries
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Links   []atomLink  `xml:"link"`
+	Updated string      `xml:"updated"`
+	Summary string      `xml:"summary,omitempty"`
+	Author  *atomPerson `xml:"author,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        string        `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Description string        `xml:"description,omitempty"`
+	Creator     string        `xml:"dc:creator,omitempty"` // `author` of rss must be an email
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length int    `xml:"length,attr"`
+}
+
+type sitemap struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// coverURL returns the absolute url of the cover resolved by `resolveURL`,
+// empty if no cover
+func coverURL(cover string) string {
+	if strings.HasPrefix(cover, "/") && !strings.HasPrefix(cover, "//") {
+		return originURL() + cover
+	}
+
+	return cover
+}
+
+// feedSlides returns the newest slides with a date, slides should be sorted by time
+func feedSlides(slides []*slideData) []*slideData {
+	var result []*slideData
+
+	for _, slide := range slides {
+		if slide.Time.IsZero() {
+			continue
+		}
+
+		result = append(result, slide)
+
+		if len(result) == maxFeedEntries {
+			break
+		}
+	}
+
+	return result
+}
+
+// feedAuthor returns the default author of the feeds, atom requires one
+func feedAuthor() string {
+	if opts.author != "" {
+		return opts.author
+	}
+
+	return siteTitle()
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	buf, err := xml.MarshalIndent(v, "", "  ")
+
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal xml")
+	}
+
+	return append([]byte(xml.Header), buf...), nil
+}
+
+func getAtomFeed(slides []*slideData) ([]byte, error) {
+	slides = feedSlides(slides)
+
+	feed := &atomFeed{
+		Title: siteTitle(),
+		ID:    absURL(""),
+		Links: []atomLink{
+			{Href: absURL("")},
+			{Rel: "self", Href: absURL("atom.xml")},
+		},
+		Author: atomPerson{feedAuthor()},
+	}
+
+	if len(slides) > 0 {
+		feed.Updated = slides[0].Time.Format(time.RFC3339)
+	} else {
+		feed.Updated = time.Now().Format(time.RFC3339)
+	}
+
+	for _, slide := range slides {
+		entry := atomEntry{
+			Title:   slide.Name,
+			ID:      absURL(slide.Path),
+			Links:   []atomLink{{Href: absURL(slide.Path)}},
+			Updated: slide.Time.Format(time.RFC3339),
+			Summary: slide.Desc,
+		}
+
+		if cover := coverURL(slide.Cover); cover != "" {
+			entry.Links = append(entry.Links, atomLink{
+				Rel:  "enclosure",
+				Href: cover,
+				Type: mime.TypeByExtension(path.Ext(cover)),
+			})
+		}
+
+		if slide.Author != "" {
+			entry.Author = &atomPerson{slide.Author}
+		}
+
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return marshalXML(feed)
+}
+
+func getRSSFeed(slides []*slideData) ([]byte, error) {
+	slides = feedSlides(slides)
+
+	feed := &rssFeed{
+		Version: "2.0",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       siteTitle(),
+			Link:        absURL(""),
+			Description: siteTitle(),
+		},
+	}
+
+	if len(slides) > 0 {
+		feed.Channel.LastBuildDate = slides[0].Time.Format(time.RFC1123Z)
+	}
+
+	for _, slide := range slides {
+		item := rssItem{
+			Title:       slide.Name,
+			Link:        absURL(slide.Path),
+			GUID:        absURL(slide.Path),
+			PubDate:     slide.Time.Format(time.RFC1123Z),
+			Description: slide.Desc,
+			Creator:     slide.Author,
+		}
+
+		if cover := coverURL(slide.Cover); cover != "" {
+			item.Enclosure = &rssEnclosure{
+				URL:  cover,
+				Type: mime.TypeByExtension(path.Ext(cover)),
+			}
+		}
+
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+
+	return marshalXML(feed)
+}
+
+// paths are relative to the site root
+func getSitemap(slides []*slideData, pages []string) ([]byte, error) {
+	result := &sitemap{}
+
+	for _, p := range pages {
+		result.URLs = append(result.URLs, sitemapURL{Loc: absURL(p)})
+	}
+
+	for _, slide := range slides {
+		u := sitemapURL{Loc: absURL(slide.Path)}
+
+		if !slide.Time.IsZero() {
+			u.LastMod = slide.Time.Format("2006-01-02")
+		}
+
+		result.URLs = append(result.URLs, u)
+	}
+
+	return marshalXML(result)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setFeedOpts sets the options used by feeds for the test
+func setFeedOpts(t *testing.T, baseURL, basePath, author string) {
+	base, path, a, content := opts.baseURL, opts.basePath, opts.author, opts.contentBase
+	t.Cleanup(func() {
+		opts.baseURL, opts.basePath, opts.author, opts.contentBase = base, path, a, content
+	})
+
+	opts.baseURL, opts.basePath, opts.author = baseURL, basePath, author
+	opts.contentBase = t.TempDir()
+}
+
+func feedTestSlides() []*slideData {
+	return []*slideData{
+		{
+			Name:   "New",
+			Path:   "2020/new.html",
+			Time:   time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC),
+			Author: "Jane Doe",
+			Desc:   "Something new",
+			Cover:  "/talks/2020/new.cover.svg",
+		},
+		{Name: "Undated", Path: "undated.html"},
+		{
+			Name: "Old",
+			Path: "2019/old.html",
+			Time: time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestGetAtomFeed(t *testing.T) {
+	setFeedOpts(t, "https://example.com/talks/", "/talks/", "")
+
+	buf, err := getAtomFeed(feedTestSlides())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(buf, &feed); err != nil {
+		t.Fatal(err)
+	}
+
+	if feed.ID != "https://example.com/talks/" || feed.Updated != "2020-05-01T00:00:00Z" {
+		t.Errorf("got id %q, updated %q", feed.ID, feed.Updated)
+	}
+
+	// atom requires an author of the feed or of every entry
+	if feed.Author.Name != defaultSiteTitle {
+		t.Errorf("got feed author %q; want %q", feed.Author.Name, defaultSiteTitle)
+	}
+
+	if len(feed.Entries) != 2 {
+		t.Fatalf("got %d entries; want 2", len(feed.Entries))
+	}
+
+	e := feed.Entries[0]
+	if e.ID != "https://example.com/talks/2020/new.html" || e.Author == nil || e.Author.Name != "Jane Doe" {
+		t.Errorf("got entry %+v", e)
+	}
+
+	if len(e.Links) != 2 || e.Links[1] != (atomLink{"enclosure", "https://example.com/talks/2020/new.cover.svg", "image/svg+xml"}) {
+		t.Errorf("got links %+v", e.Links)
+	}
+
+	if e := feed.Entries[1]; e.Title != "Old" || e.Author != nil {
+		t.Errorf("got entry %+v", e)
+	}
+
+	setFeedOpts(t, "https://example.com/", "/", "John")
+
+	buf, err = getAtomFeed(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(buf), "<author>\n    <name>John</name>\n  </author>") {
+		t.Errorf("feed author is not set by --author:\n%s", buf)
+	}
+}
+
+func TestGetRSSFeed(t *testing.T) {
+	setFeedOpts(t, "https://example.com/", "/", "")
+
+	buf, err := getRSSFeed(feedTestSlides())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := string(buf)
+
+	for _, want := range []string{
+		`<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">`,
+		"<link>https://example.com/</link>",
+		"<dc:creator>Jane Doe</dc:creator>",
+		"<pubDate>Fri, 01 May 2020 00:00:00 +0000</pubDate>",
+		"<lastBuildDate>Fri, 01 May 2020 00:00:00 +0000</lastBuildDate>",
+		`<enclosure url="https://example.com/talks/2020/new.cover.svg" type="image/svg+xml" length="0"></enclosure>`,
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("rss feed does not contain %q:\n%s", want, s)
+		}
+	}
+
+	if strings.Contains(s, "<author>") {
+		t.Errorf("rss author must be an email:\n%s", s)
+	}
+
+	if n := strings.Count(s, "<item>"); n != 2 {
+		t.Errorf("got %d items; want 2", n)
+	}
+}
+
+func TestGetSitemap(t *testing.T) {
+	setFeedOpts(t, "https://example.com/talks/", "/talks/", "")
+
+	buf, err := getSitemap(feedTestSlides(), []string{"", "tags/go/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sm sitemap
+	if err := xml.Unmarshal(buf, &sm); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []sitemapURL{
+		{Loc: "https://example.com/talks/"},
+		{Loc: "https://example.com/talks/tags/go/"},
+		{Loc: "https://example.com/talks/2020/new.html", LastMod: "2020-05-01"},
+		{Loc: "https://example.com/talks/undated.html"},
+		{Loc: "https://example.com/talks/2019/old.html", LastMod: "2019-01-02"},
+	}
+
+	if len(sm.URLs) != len(want) {
+		t.Fatalf("got %d urls; want %d", len(sm.URLs), len(want))
+	}
+
+	for i := range want {
+		if sm.URLs[i] != want[i] {
+			t.Errorf("url %d: got %+v; want %+v", i, sm.URLs[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// readFileAt implements present.Context.ReadFileAt with the git binary,
+// the file is read from the repository containing it
+func readFileAt(filename, rev string) ([]byte, error) {
+	// a revision starting with `-` would be parsed as an option by git
+	if strings.HasPrefix(rev, "-") {
+		return nil, errors.Errorf("invalid revision: %s", rev)
+	}
+
+	cmd := exec.Command("git", "show", rev+":./"+filepath.Base(filename))
+	cmd.Dir = filepath.Dir(filename)
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, errors.Errorf("could not read %s at revision %s: %s", filename, rev, msg)
+	}
+
+	return out, nil
+}
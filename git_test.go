@@ -0,0 +1,74 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadFileAt(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	file := filepath.Join(repo, "code", "main.go")
+
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{
+			"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false",
+		}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	commit := func(content, tag string) {
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", ".")
+		git("commit", "-q", "-m", tag)
+		git("tag", tag)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	git("init", "-q")
+	commit("v1\n", "v1")
+	commit("v2\n", "v2")
+
+	tests := []struct {
+		rev  string
+		want string
+		err  string
+	}{
+		{rev: "v1", want: "v1\n"},
+		{rev: "v2", want: "v2\n"},
+		{rev: "HEAD~1", want: "v1\n"},
+		{rev: "v3", err: "could not read"},
+		{rev: "--output=x", err: "invalid revision"},
+	}
+
+	for _, tt := range tests {
+		buf, err := readFileAt(file, tt.rev)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: got error %v; want %q", tt.rev, err, tt.err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %v", tt.rev, err)
+		} else if string(buf) != tt.want {
+			t.Errorf("%s: got %q; want %q", tt.rev, buf, tt.want)
+		}
+	}
+}
@@ -1,16 +1,25 @@
 module github.com/cj1128/mypresent
 
-go 1.12
+go 1.20
 
 require (
-	github.com/alecthomas/template v0.0.0-20160405071501-a0175ee3bccc // indirect
-	github.com/alecthomas/units v0.0.0-20151022065526-2efee857e7cf // indirect
 	github.com/gobuffalo/packr v1.30.1
 	github.com/k0kubun/pp v3.0.1+incompatible
 	github.com/kataras/golog v0.0.0-20190624001437-99c81de45f40
-	github.com/kataras/pio v0.0.0-20190103105442-ea782b38602d // indirect
-	github.com/mattn/go-colorable v0.1.1 // indirect
 	github.com/pkg/errors v0.8.1
 	github.com/sirupsen/logrus v1.4.2
+	golang.org/x/image v0.15.0
 	gopkg.in/alecthomas/kingpin.v2 v2.2.6
 )
+
+require (
+	github.com/alecthomas/template v0.0.0-20160405071501-a0175ee3bccc // indirect
+	github.com/alecthomas/units v0.0.0-20151022065526-2efee857e7cf // indirect
+	github.com/gobuffalo/envy v1.7.0 // indirect
+	github.com/gobuffalo/packd v0.3.0 // indirect
+	github.com/joho/godotenv v1.3.0 // indirect
+	github.com/kataras/pio v0.0.0-20190103105442-ea782b38602d // indirect
+	github.com/mattn/go-colorable v0.1.1 // indirect
+	github.com/rogpeppe/go-internal v1.3.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/cj1128/mypresent/present"
+	"github.com/kataras/golog"
+	"github.com/pkg/errors"
+)
+
+// widths of the generated image variants, only those smaller than
+// the original image are generated
+var imageWidths = []int{480, 960, 1920}
+
+// width of the cover thumbnails on index pages
+const thumbWidth = 480
+
+var imageVariantsCache = struct {
+	sync.Mutex
+	m     map[string][]present.ImageVariant
+	files map[string]string // output path -> cached file
+}{
+	m:     make(map[string][]present.ImageVariant),
+	files: make(map[string]string),
+}
+
+// imageVariants implements present.Context.ImageVariants
+// variants are only generated by build, they are cached in `opts.cacheDir`
+// by the hash of the original image and written next to the original image
+// in the output by `writeImageVariants`, e.g. `img/a.png` -> `img/a.960w.png`
+// the original image is the last variant
+func imageVariants(u string) []present.ImageVariant {
+	if !opts.resizeImages {
+		return nil
+	}
+
+	imageVariantsCache.Lock()
+	defer imageVariantsCache.Unlock()
+
+	if v, ok := imageVariantsCache.m[u]; ok {
+		return v
+	}
+
+	v, err := generateImageVariants(u)
+	if err != nil {
+		golog.Warnf("could not generate variants of image %s: %v", u, err)
+	}
+
+	imageVariantsCache.m[u] = v
+
+	return v
+}
+
+// thumbnail returns the url of the cover thumbnail, or the cover itself
+func thumbnail(cover string) string {
+	for _, v := range imageVariants(cover) {
+		if v.Width >= thumbWidth {
+			return v.URL
+		}
+	}
+
+	return cover
+}
+
+// u is an url resolved by `resolveURL`
+// imageVariantsCache must be locked
+func generateImageVariants(u string) ([]present.ImageVariant, error) {
+	// only local images under base path
+	if !strings.HasPrefix(u, opts.basePath) || strings.HasPrefix(u, "//") {
+		return nil, nil
+	}
+
+	p := strings.TrimPrefix(u, opts.basePath)
+	if strings.ContainsAny(p, "?#") {
+		return nil, nil
+	}
+
+	ext := strings.ToLower(path.Ext(p))
+	if ext != ".png" && ext != ".jpg" && ext != ".jpeg" {
+		return nil, nil
+	}
+
+	src, err := ioutil.ReadFile(filepath.Join(opts.contentBase, filepath.FromSlash(p)))
+	if err != nil {
+		return nil, nil // reported by resolveURL
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(src))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode image")
+	}
+
+	sum := sha256.Sum256(src)
+	hash := hex.EncodeToString(sum[:])
+
+	var img image.Image
+	var result []present.ImageVariant
+
+	for _, width := range imageWidths {
+		if width >= config.Width {
+			break
+		}
+
+		name := fmt.Sprintf("%s.%dw%s", strings.TrimSuffix(p, path.Ext(p)), width, path.Ext(p))
+		cached := filepath.Join(opts.cacheDir, fmt.Sprintf("%s-%d%s", hash, width, ext))
+
+		if !fileExists(cached) {
+			if img == nil {
+				if img, _, err = image.Decode(bytes.NewReader(src)); err != nil {
+					return nil, errors.Wrap(err, "could not decode image")
+				}
+			}
+
+			if err := writeResized(cached, img, width, ext); err != nil {
+				return nil, err
+			}
+		}
+
+		imageVariantsCache.files[name] = cached
+
+		result = append(result, present.ImageVariant{
+			URL:   siteURL(name),
+			Width: width,
+		})
+	}
+
+	if len(result) > 0 {
+		result = append(result, present.ImageVariant{
+			URL:   u,
+			Width: config.Width,
+		})
+	}
+
+	return result, nil
+}
+
+// writeImageVariants copies the generated variants to the output
+func writeImageVariants() error {
+	imageVariantsCache.Lock()
+	defer imageVariantsCache.Unlock()
+
+	for name, cached := range imageVariantsCache.files {
+		if err := copyFile(cached, filepath.Join(opts.output, filepath.FromSlash(name))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeResized(dst string, img image.Image, width int, ext string) error {
+	buf := &bytes.Buffer{}
+	resized := resize(img, width)
+
+	var err error
+	if ext == ".png" {
+		err = png.Encode(buf, resized)
+	} else {
+		err = jpeg.Encode(buf, resized, &jpeg.Options{Quality: 85})
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "could not encode image")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.Wrap(err, "could not create cache dir")
+	}
+
+	return ioutil.WriteFile(dst, buf.Bytes(), 0644)
+}
+
+func copyFile(src, dst string) error {
+	buf, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.Wrap(err, "could not create dir")
+	}
+
+	return ioutil.WriteFile(dst, buf, 0644)
+}
+
+// resize scales img down to width, keeping the aspect ratio
+// each destination pixel is the average of the source pixels it covers
+func resize(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	height := b.Dy() * width / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	src := image.NewNRGBA(b)
+	draw.Draw(src, b, img, b.Min, draw.Src)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * b.Dy() / height
+		y1 := (y + 1) * b.Dy() / height
+
+		for x := 0; x < width; x++ {
+			x0 := x * b.Dx() / width
+			x1 := (x + 1) * b.Dx() / width
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := src.NRGBAAt(b.Min.X+sx, b.Min.Y+sy)
+					r += uint64(c.R)
+					g += uint64(c.G)
+					bl += uint64(c.B)
+					a += uint64(c.A)
+					n++
+				}
+			}
+
+			if n > 0 {
+				dst.SetNRGBA(x, y, color.NRGBA{
+					R: uint8(r / n),
+					G: uint8(g / n),
+					B: uint8(bl / n),
+					A: uint8(a / n),
+				})
+			}
+		}
+	}
+
+	return dst
+}
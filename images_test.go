@@ -0,0 +1,100 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cj1128/mypresent/present"
+)
+
+func TestResize(t *testing.T) {
+	// left half black, right half white
+	img := image.NewNRGBA(image.Rect(10, 10, 14, 12))
+	for y := 10; y < 12; y++ {
+		for x := 10; x < 14; x++ {
+			c := color.NRGBA{0, 0, 0, 255}
+			if x >= 12 {
+				c = color.NRGBA{255, 255, 255, 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	tests := []struct {
+		width  int
+		size   image.Point
+		pixels []color.NRGBA
+	}{
+		{2, image.Pt(2, 1), []color.NRGBA{{0, 0, 0, 255}, {255, 255, 255, 255}}},
+		{1, image.Pt(1, 1), []color.NRGBA{{127, 127, 127, 255}}},
+	}
+
+	for _, tt := range tests {
+		got := resize(img, tt.width).(*image.NRGBA)
+
+		if size := got.Bounds().Size(); size != tt.size {
+			t.Errorf("resize to %d: got size %v; want %v", tt.width, size, tt.size)
+			continue
+		}
+
+		for i, want := range tt.pixels {
+			if c := got.NRGBAAt(i, 0); c != want {
+				t.Errorf("resize to %d: pixel %d is %v; want %v", tt.width, i, c, want)
+			}
+		}
+	}
+}
+
+func TestGenerateImageVariants(t *testing.T) {
+	defer func(content, cache, base string) {
+		opts.contentBase, opts.cacheDir, opts.basePath = content, cache, base
+	}(opts.contentBase, opts.cacheDir, opts.basePath)
+
+	opts.contentBase = t.TempDir()
+	opts.cacheDir = t.TempDir()
+	opts.basePath = "/talks/"
+	imageVariantsCache.files = make(map[string]string)
+
+	for name, width := range map[string]int{"big.png": 1000, "small.png": 300} {
+		f, err := os.Create(filepath.Join(opts.contentBase, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		png.Encode(f, image.NewNRGBA(image.Rect(0, 0, width, 10)))
+		f.Close()
+	}
+
+	tests := []struct {
+		url      string
+		variants []present.ImageVariant
+	}{
+		{"/talks/big.png", []present.ImageVariant{
+			{URL: "/talks/big.480w.png", Width: 480},
+			{URL: "/talks/big.960w.png", Width: 960},
+			{URL: "/talks/big.png", Width: 1000},
+		}},
+		{"/talks/small.png", nil},
+		{"/talks/missing.png", nil},
+		{"/talks/big.png?v=1", nil},
+		{"/other/big.png", nil},
+		{"https://example.com/big.png", nil},
+	}
+
+	for _, tt := range tests {
+		v, err := generateImageVariants(tt.url)
+		if err != nil {
+			t.Errorf("%s: %v", tt.url, err)
+		} else if !reflect.DeepEqual(v, tt.variants) {
+			t.Errorf("%s: got variants %v; want %v", tt.url, v, tt.variants)
+		}
+	}
+
+	if n := len(imageVariantsCache.files); n != 2 {
+		t.Errorf("got %d variant files; want 2", n)
+	}
+}
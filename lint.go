@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cj1128/mypresent/present"
+	"github.com/kataras/golog"
+)
+
+// lintContent parses all slides and reports their errors, e.g. missing
+// code files or revisions of `.code file@rev` that can't be resolved
+func lintContent() {
+	count := 0
+
+	err := filepath.Walk(opts.contentBase, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !isSlide(p) {
+			return nil
+		}
+
+		path, _ := filepath.Rel(opts.contentBase, p)
+
+		if _, err := parseSlide(path, present.FullMode); err != nil {
+			golog.Error(err)
+			count++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		golog.Fatal(err)
+	}
+
+	if count > 0 {
+		golog.Fatalf("found errors in %d slides", count)
+	}
+
+	golog.Info("no errors found")
+}
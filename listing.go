@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// listing is a group of slides sharing a tag, an event or a year
+type listing struct {
+	Name   string
+	Path   string // url path of the listing page, e.g. `tags/go/`
+	Slides []*slideData
+}
+
+// catalog holds all the listings generated from the slides
+type catalog struct {
+	Tags   []*listing
+	Events []*listing
+	Years  []*listing
+}
+
+// indexPage is the data passed to index.tmpl
+type indexPage struct {
+	Title       string // empty for the top level index
+	Index       *indexData
+	Dir         *indexData   // directory of the page, nil for listing pages
+	Breadcrumbs []*indexData // parent directories of Dir, top level first
+	All         []*slideData
+	*catalog
+}
+
+// slides should be sorted by time, listings keep that order
+func newCatalog(slides []*slideData) *catalog {
+	tags := make(map[string]*listing)
+	events := make(map[string]*listing)
+	years := make(map[string]*listing)
+
+	// names differing only in case share a listing
+	add := func(m map[string]*listing, name string, slide *slideData) {
+		if slugify(name) == "" {
+			return
+		}
+
+		key := strings.ToLower(name)
+
+		l := m[key]
+		if l == nil {
+			l = &listing{Name: name}
+			m[key] = l
+		}
+
+		l.Slides = append(l.Slides, slide)
+	}
+
+	for _, slide := range slides {
+		for _, tag := range slide.Tags {
+			add(tags, tag, slide)
+		}
+
+		if slide.Event != "" {
+			add(events, slide.Event, slide)
+		}
+
+		if !slide.Time.IsZero() {
+			add(years, strconv.Itoa(slide.Time.Year()), slide)
+		}
+	}
+
+	result := &catalog{
+		Tags:   sortedListings(tags, "tags/"),
+		Events: sortedListings(events, "events/"),
+		Years:  sortedListings(years, "years/"),
+	}
+
+	// newest year first
+	sort.SliceStable(result.Years, func(i, j int) bool {
+		return result.Years[i].Name > result.Years[j].Name
+	})
+
+	return result
+}
+
+// find the listing page for the url path, path has no leading slash
+func (c *catalog) find(path string) (title string, l *listing) {
+	path = strings.TrimSuffix(path, "/") + "/"
+
+	for _, group := range []struct {
+		title    string
+		listings []*listing
+	}{
+		{"Tag", c.Tags},
+		{"Event", c.Events},
+		{"Year", c.Years},
+	} {
+		for _, l := range group.listings {
+			if l.Path == path {
+				return group.title + ": " + l.Name, l
+			}
+		}
+	}
+
+	return "", nil
+}
+
+func (c *catalog) all() []*listing {
+	var result []*listing
+	result = append(result, c.Tags...)
+	result = append(result, c.Events...)
+	result = append(result, c.Years...)
+	return result
+}
+
+// sortedListings sorts the listings by name and sets their paths under
+// prefix. Names with the same slug, e.g. `C` and `C++`, get the bare slug
+// if the name is the slug itself, or else the first of them does, the
+// others get a suffix from the hash of the name, e.g. `c-3f2a1b/`, so their
+// paths don't change when other names are added
+func sortedListings(m map[string]*listing, prefix string) []*listing {
+	result := make([]*listing, 0, len(m))
+
+	for _, l := range m {
+		result = append(result, l)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+
+	owners := make(map[string]*listing)
+
+	for _, l := range result {
+		slug := slugify(l.Name)
+		if owners[slug] == nil || strings.ToLower(l.Name) == slug {
+			owners[slug] = l
+		}
+	}
+
+	for _, l := range result {
+		slug := slugify(l.Name)
+		if owners[slug] != l {
+			slug += "-" + nameHash(l.Name)
+		}
+
+		l.Path = prefix + slug + "/"
+	}
+
+	return result
+}
+
+// nameHash returns 6 hex digits of the hash of the name, ignoring case
+func nameHash(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(name)))
+	return fmt.Sprintf("%06x", h.Sum32()&0xffffff)
+}
+
+// slugify turns `Go Concurrency` into `go-concurrency`
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+
+		dash = true
+	}
+
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		{"Go", "go"},
+		{"Go Concurrency", "go-concurrency"},
+		{"  GopherCon  2019! ", "gophercon-2019"},
+		{"C++", "c"},
+		{"node.js", "node-js"},
+		{"并发编程", "并发编程"},
+		{"Go 并发", "go-并发"},
+		{"++", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := slugify(tt.in); got != tt.out {
+			t.Errorf("slugify(%q) = %q; want %q", tt.in, got, tt.out)
+		}
+	}
+}
+
+func TestNewCatalog(t *testing.T) {
+	date := func(year int) time.Time {
+		return time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	a := &slideData{Name: "a", Time: date(2019), Tags: []string{"Go", "C++"}, Event: "GopherCon"}
+	b := &slideData{Name: "b", Time: date(2020), Tags: []string{"go", "C"}}
+	c := &slideData{Name: "c", Tags: []string{"c 2", "!!"}}
+
+	cat := newCatalog([]*slideData{a, b, c})
+
+	type entry struct {
+		Name   string
+		Path   string
+		Slides []*slideData
+	}
+
+	entries := func(ls []*listing) []entry {
+		var result []entry
+		for _, l := range ls {
+			result = append(result, entry{l.Name, l.Path, l.Slides})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name string
+		got  []*listing
+		want []entry
+	}{
+		{"tags", cat.Tags, []entry{
+			{"C", "tags/c/", []*slideData{b}},
+			{"c 2", "tags/c-2/", []*slideData{c}},
+			{"C++", "tags/c-21a3f0/", []*slideData{a}},
+			{"Go", "tags/go/", []*slideData{a, b}},
+		}},
+		{"events", cat.Events, []entry{
+			{"GopherCon", "events/gophercon/", []*slideData{a}},
+		}},
+		{"years", cat.Years, []entry{
+			{"2020", "years/2020/", []*slideData{b}},
+			{"2019", "years/2019/", []*slideData{a}},
+		}},
+	}
+
+	for _, tt := range tests {
+		if got := entries(tt.got); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v; want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	for path, title := range map[string]string{
+		"tags/c-21a3f0": "Tag: C++",
+		"years/2019/":   "Year: 2019",
+		"2019/":         "",
+	} {
+		if got, _ := cat.find(path); got != title {
+			t.Errorf("find(%q) = %q; want %q", path, got, title)
+		}
+	}
+
+	// paths don't change when names with the same slug are added
+	d := &slideData{Name: "d", Tags: []string{"C#"}}
+	for _, l := range newCatalog([]*slideData{a, b, c, d}).Tags {
+		if l.Name == "C++" && l.Path != "tags/c-21a3f0/" {
+			t.Errorf("C++ moved to %s", l.Path)
+		}
+	}
+}
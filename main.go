@@ -13,17 +13,29 @@ var assetBox = packr.NewBox("./static")
 
 var (
 	opts struct {
-		host         string
-		port         int
-		resourcePath string
-		contentBase  string
-		output       string
-		notesEnabled bool
+		host            string
+		port            int
+		resourcePath    string
+		contentBase     string
+		output          string
+		baseURL         string
+		basePath        string
+		notesEnabled    bool
+		playEnabled     bool
+		includeDrafts   bool
+		resizeImages    bool
+		cacheDir        string
+		allowedCommands []string
+		runCommands     bool
+		coverFont       string
+		author          string
 	}
 
 	indexTemplate *template.Template
 
 	slideTemplate *template.Template
+
+	searchTemplate *template.Template
 )
 
 func parseFlags() string {
@@ -37,6 +49,21 @@ func parseFlags() string {
 		Default(".").
 		StringVar(&opts.contentBase)
 
+	kingpin.Flag("base-url", "public url of the site, e.g. https://slides.example.com, used in feeds, sitemap and metadata").
+		StringVar(&opts.baseURL)
+
+	kingpin.Flag("base-path", "path the site is hosted under, e.g. /talks/, default is the path of base url").
+		StringVar(&opts.basePath)
+
+	kingpin.Flag("cache-dir", "cache path of generated files and command outputs, default is the user cache dir").
+		StringVar(&opts.cacheDir)
+
+	kingpin.Flag("allow-command", "command allowed to run by .output, e.g. \"go test\", can be repeated").
+		StringsVar(&opts.allowedCommands)
+
+	kingpin.Flag("cover-font", "font file (ttf, otf or ttc) of png cover cards, e.g. a CJK font, Go fonts are used for missing characters").
+		StringVar(&opts.coverFont)
+
 	// serve flags
 	serve := kingpin.Command("serve", "Start the server").Default()
 	serve.Flag("host", "server host").
@@ -53,6 +80,10 @@ func parseFlags() string {
 		Default("false").
 		BoolVar(&opts.notesEnabled)
 
+	serve.Flag("play", "enable running .play code blocks with the local go toolchain").
+		Default("false").
+		BoolVar(&opts.playEnabled)
+
 	// build flags
 	build := kingpin.Command("build", "Generate output")
 	build.Flag("output", "output path").
@@ -60,6 +91,20 @@ func parseFlags() string {
 		Default("dist").
 		StringVar(&opts.output)
 
+	build.Flag("include-drafts", "include draft and scheduled slides, for previews").
+		Default("false").
+		BoolVar(&opts.includeDrafts)
+
+	build.Flag("author", "author of the feeds, default is the site title, slides use their own .author").
+		StringVar(&opts.author)
+
+	build.Flag("resize-images", "generate resized variants of local images").
+		Default("true").
+		BoolVar(&opts.resizeImages)
+
+	// lint
+	kingpin.Command("lint", "Check slides for errors, e.g. missing code files or revisions")
+
 	kingpin.HelpFlag.Short('h')
 
 	return kingpin.Parse()
@@ -67,7 +112,10 @@ func parseFlags() string {
 
 func initTemplates() {
 	var err error
-	parent := present.Template()
+	parent := present.Template().Funcs(template.FuncMap{
+		"url":       siteURL,
+		"siteTitle": siteTitle,
+	})
 
 	slideTemplate, err = initTemplate("tmpl/slide.tmpl", parent)
 	if err != nil {
@@ -78,11 +126,20 @@ func initTemplates() {
 	if err != nil {
 		golog.Fatal(err)
 	}
+
+	searchTemplate, err = initTemplate("tmpl/search.tmpl", parent)
+	if err != nil {
+		golog.Fatal(err)
+	}
 }
 
 func main() {
 	cmd := parseFlags()
 
+	normalizeBasePath()
+
+	normalizeCacheDir()
+
 	initTemplates()
 
 	switch cmd {
@@ -91,5 +148,8 @@ func main() {
 
 	case "build":
 		buildContent()
+
+	case "lint":
+		lintContent()
 	}
 }
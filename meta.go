@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/cj1128/mypresent/present"
+)
+
+// slideMeta is the data for Open Graph, Twitter card and JSON-LD metadata
+// of a slide, rendered by the `meta` template in slide.tmpl
+type slideMeta struct {
+	Title       string
+	Description string
+	Image       string // absolute url of the cover, png for generated cover cards
+	URL         string // canonical url, empty if base url is not provided
+	Published   string
+	Author      string
+	Tags        []string
+	JSONLD      template.JS
+}
+
+// https://schema.org/PresentationDigitalDocument
+type jsonLD struct {
+	Context       string        `json:"@context"`
+	Type          string        `json:"@type"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description,omitempty"`
+	URL           string        `json:"url,omitempty"`
+	Image         string        `json:"image,omitempty"`
+	DatePublished string        `json:"datePublished,omitempty"`
+	Author        *jsonLDPerson `json:"author,omitempty"`
+	Keywords      string        `json:"keywords,omitempty"`
+	About         *jsonLDEvent  `json:"about,omitempty"`
+}
+
+type jsonLDPerson struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+type jsonLDEvent struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+// newSlideMeta generates metadata from the document header
+// urlPath is the url path of the generated slide page
+// absolute urls are only available when `opts.baseURL` is provided
+func newSlideMeta(doc *present.Doc, urlPath string) (*slideMeta, error) {
+	result := &slideMeta{
+		Title:       doc.Title,
+		Description: doc.Subtitle,
+		Author:      doc.Author,
+		Tags:        doc.Tags,
+	}
+
+	if result.Description == "" {
+		result.Description = doc.Title
+	}
+
+	if !doc.Time.IsZero() {
+		result.Published = doc.Time.Format(time.RFC3339)
+	}
+
+	if opts.baseURL != "" {
+		result.URL = absURL(urlPath)
+		result.Image = coverURL(shareImage(doc.Cover))
+	}
+
+	ld := &jsonLD{
+		Context:       "https://schema.org",
+		Type:          "PresentationDigitalDocument",
+		Name:          result.Title,
+		Description:   doc.Subtitle,
+		URL:           result.URL,
+		Image:         result.Image,
+		DatePublished: result.Published,
+		Keywords:      strings.Join(doc.Tags, ", "),
+	}
+
+	if doc.Author != "" {
+		ld.Author = &jsonLDPerson{"Person", doc.Author}
+	}
+
+	if doc.Event != "" {
+		ld.About = &jsonLDEvent{"Event", doc.Event}
+	}
+
+	buf, err := json.Marshal(ld)
+	if err != nil {
+		return nil, err
+	}
+
+	result.JSONLD = template.JS(buf)
+
+	return result, nil
+}
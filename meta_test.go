@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cj1128/mypresent/present"
+)
+
+func TestNewSlideMeta(t *testing.T) {
+	tests := []struct {
+		name           string
+		baseURL, cover string
+		url, image     string
+	}{
+		{
+			name:  "no base url",
+			cover: "/talks/2019/talk.png",
+		},
+		{
+			name:    "local cover",
+			baseURL: "https://example.com/talks/",
+			cover:   "/talks/2019/talk.png",
+			url:     "https://example.com/talks/2019/talk.html",
+			image:   "https://example.com/talks/2019/talk.png",
+		},
+		{
+			name:    "generated cover card",
+			baseURL: "https://example.com/talks/",
+			cover:   "/talks/2019/talk.cover.svg",
+			url:     "https://example.com/talks/2019/talk.html",
+			image:   "https://example.com/talks/2019/talk.cover.png",
+		},
+		{
+			name:    "absolute cover",
+			baseURL: "https://example.com/talks/",
+			cover:   "https://cdn.example.com/talk.png",
+			url:     "https://example.com/talks/2019/talk.html",
+			image:   "https://cdn.example.com/talk.png",
+		},
+	}
+
+	for _, tt := range tests {
+		setFeedOpts(t, tt.baseURL, "/talks/", "")
+
+		doc := &present.Doc{
+			Title:    "Talk",
+			Subtitle: "About talks",
+			Time:     time.Date(2019, 3, 4, 0, 0, 0, 0, time.UTC),
+			Cover:    tt.cover,
+		}
+
+		meta, err := newSlideMeta(doc, "2019/talk.html")
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+
+		if meta.URL != tt.url || meta.Image != tt.image {
+			t.Errorf("%s: got url %q, image %q; want %q, %q", tt.name, meta.URL, meta.Image, tt.url, tt.image)
+		}
+
+		var ld jsonLD
+		if err := json.Unmarshal([]byte(meta.JSONLD), &ld); err != nil {
+			t.Errorf("%s: invalid JSON-LD: %v", tt.name, err)
+		} else if ld.URL != tt.url || ld.Image != tt.image || ld.DatePublished != "2019-03-04T00:00:00Z" {
+			t.Errorf("%s: got JSON-LD %+v", tt.name, ld)
+		}
+	}
+}
+
+func TestNewSlideMetaCoverCard(t *testing.T) {
+	setFeedOpts(t, "https://example.com/talks/", "/talks/", "")
+
+	p := filepath.Join(opts.contentBase, "2019", "talk.slide")
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(p, []byte("Talk\nAbout talks\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := parseSlide("2019/talk.slide", present.TitlesOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := newSlideMeta(doc, "2019/talk.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// social sites don't show svg, the png card is used
+	if want := "https://example.com/talks/2019/talk.cover.png"; meta.Image != want {
+		t.Errorf("got image %q; want %q", meta.Image, want)
+	}
+}
+
+func TestNewSlideMetaEscape(t *testing.T) {
+	setFeedOpts(t, "", "/", "")
+
+	doc := &present.Doc{
+		Title:  "</script><script>alert(1)</script>",
+		Author: "A & B",
+	}
+
+	meta, err := newSlideMeta(doc, "talk.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := string(meta.JSONLD); strings.Contains(s, "</script>") || strings.Contains(s, "<script>") {
+		t.Errorf("JSON-LD is not escaped: %s", s)
+	}
+
+	var ld jsonLD
+	if err := json.Unmarshal([]byte(meta.JSONLD), &ld); err != nil {
+		t.Fatal(err)
+	}
+	if ld.Name != doc.Title || ld.Author == nil || ld.Author.Name != doc.Author {
+		t.Errorf("got JSON-LD %+v", ld)
+	}
+}
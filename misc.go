@@ -3,9 +3,13 @@ package main
 import (
 	"html/template"
 	"io/ioutil"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
+	"github.com/kataras/golog"
 	"github.com/pkg/errors"
 )
 
@@ -16,7 +20,7 @@ func initTemplate(path string, parent *template.Template) (*template.Template, e
 		return nil, errors.Wrap(err, "could not get asset")
 	}
 
-	return parent.New("").Parse(string(buf))
+	return parent.New(path).Parse(string(buf))
 }
 
 // get asset from user specified resource dir
@@ -33,3 +37,88 @@ func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return !os.IsNotExist(err)
 }
+
+// siteURL returns the url of p with `opts.basePath` prepended,
+// p is relative to the site root, with or without a leading slash
+// absolute urls are returned as is
+func siteURL(p string) string {
+	if u, err := url.Parse(p); err == nil && (u.IsAbs() || u.Host != "") {
+		return p
+	}
+
+	return opts.basePath + strings.TrimPrefix(p, "/")
+}
+
+// absURL returns the absolute url of p, p is relative to the site root
+func absURL(p string) string {
+	return originURL() + siteURL(p)
+}
+
+// originURL returns scheme and host of `opts.baseURL`
+func originURL() string {
+	u, err := url.Parse(opts.baseURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	return u.Scheme + "://" + u.Host
+}
+
+// resolveURL implements present.Context.ResolveURL
+// local references are resolved against the directory of the slide file,
+// or the content base if they start with a slash, and returned as urls
+// with base path, so they work the same in serve and build
+// missing local files are reported as warnings
+func resolveURL(filename, ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil || u.IsAbs() || u.Host != "" || u.Path == "" {
+		return ref
+	}
+
+	p := u.Path
+	if !path.IsAbs(p) {
+		p = path.Join(path.Dir(filepath.ToSlash(filename)), p)
+
+		rel, err := filepath.Rel(opts.contentBase, filepath.FromSlash(p))
+		if err != nil || strings.HasPrefix(rel, "..") {
+			golog.Warnf("%s: %s is outside of content path", filename, ref)
+			return ref
+		}
+
+		p = filepath.ToSlash(rel)
+	}
+
+	if !fileExists(filepath.Join(opts.contentBase, filepath.FromSlash(p))) {
+		golog.Warnf("%s: referenced file does not exist: %s", filename, ref)
+	}
+
+	u.Path = p
+	return siteURL(u.String())
+}
+
+// normalizeCacheDir uses the user cache dir if `opts.cacheDir` is not provided
+func normalizeCacheDir() {
+	if opts.cacheDir != "" {
+		return
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		golog.Fatalf("could not get user cache dir: %v", err)
+	}
+
+	opts.cacheDir = filepath.Join(dir, "mypresent")
+}
+
+// normalizeBasePath makes sure base path starts and ends with a slash,
+// if not provided, use the path of `opts.baseURL`
+func normalizeBasePath() {
+	if opts.basePath == "" {
+		if u, err := url.Parse(opts.baseURL); err == nil {
+			opts.basePath = u.Path
+		}
+	}
+
+	opts.basePath = "/" + strings.Trim(opts.basePath, "/") + "/"
+	opts.basePath = strings.Replace(opts.basePath, "//", "/", -1)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestNormalizeBasePath(t *testing.T) {
+	defer func(basePath, baseURL string) {
+		opts.basePath, opts.baseURL = basePath, baseURL
+	}(opts.basePath, opts.baseURL)
+
+	tests := []struct {
+		basePath, baseURL string
+		result            string
+	}{
+		{"", "", "/"},
+		{"/", "", "/"},
+		{"talks", "", "/talks/"},
+		{"/talks", "", "/talks/"},
+		{"//talks//", "", "/talks/"},
+		{"/a/b/", "", "/a/b/"},
+		{"", "https://example.com", "/"},
+		{"", "https://example.com/talks", "/talks/"},
+		{"", "https://example.com/talks/", "/talks/"},
+		{"/other/", "https://example.com/talks/", "/other/"},
+	}
+
+	for _, tt := range tests {
+		opts.basePath, opts.baseURL = tt.basePath, tt.baseURL
+		normalizeBasePath()
+
+		if opts.basePath != tt.result {
+			t.Errorf("base path %q, base url %q: got %q; want %q", tt.basePath, tt.baseURL, opts.basePath, tt.result)
+		}
+	}
+}
+
+func TestSiteURL(t *testing.T) {
+	defer func(basePath, baseURL string) {
+		opts.basePath, opts.baseURL = basePath, baseURL
+	}(opts.basePath, opts.baseURL)
+
+	opts.basePath, opts.baseURL = "/talks/", "https://example.com:8080/talks/"
+
+	tests := []struct {
+		in, site string
+	}{
+		{"", "/talks/"},
+		{"static/slide.js", "/talks/static/slide.js"},
+		{"/2019/a.html", "/talks/2019/a.html"},
+		{"https://cdn.com/a.png", "https://cdn.com/a.png"},
+		{"//cdn.com/a.png", "//cdn.com/a.png"},
+	}
+
+	for _, tt := range tests {
+		if got := siteURL(tt.in); got != tt.site {
+			t.Errorf("siteURL(%q) = %q; want %q", tt.in, got, tt.site)
+		}
+	}
+
+	if got := absURL("a.html"); got != "https://example.com:8080/talks/a.html" {
+		t.Errorf("absURL = %q", got)
+	}
+
+	opts.baseURL = ""
+	if got := absURL("a.html"); got != "/talks/a.html" {
+		t.Errorf("absURL without base url = %q", got)
+	}
+}
+
+func TestResolveURL(t *testing.T) {
+	defer func(basePath string) { opts.basePath = basePath }(opts.basePath)
+	opts.basePath = "/talks/"
+
+	writeContent(t, map[string]string{
+		"img/logo.png":           "",
+		"2019/go/img/a.png":      "",
+		"2019/go/talk.slide":     "",
+		"2019/shared/b.png":      "",
+		"2019/go/video.mp4":      "",
+		"2019/go/with space.png": "",
+	})
+
+	slide := opts.contentBase + "/2019/go/talk.slide"
+
+	tests := []struct {
+		ref, url string
+	}{
+		{"img/a.png", "/talks/2019/go/img/a.png"},
+		{"./img/a.png", "/talks/2019/go/img/a.png"},
+		{"../shared/b.png", "/talks/2019/shared/b.png"},
+		{"/img/logo.png", "/talks/img/logo.png"},
+		{"video.mp4#t=10", "/talks/2019/go/video.mp4#t=10"},
+		{"img/a.png?v=2", "/talks/2019/go/img/a.png?v=2"},
+		{"with%20space.png", "/talks/2019/go/with%20space.png"},
+		// missing files are only warned
+		{"missing.png", "/talks/2019/go/missing.png"},
+		// kept as is
+		{"https://example.com/a.png", "https://example.com/a.png"},
+		{"//cdn.example.com/a.png", "//cdn.example.com/a.png"},
+		{"data:image/png;base64,AAAA", "data:image/png;base64,AAAA"},
+		{"#top", "#top"},
+		{"../../../etc/passwd", "../../../etc/passwd"},
+	}
+
+	for _, tt := range tests {
+		if got := resolveURL(slide, tt.ref); got != tt.url {
+			t.Errorf("resolveURL(%q) = %q; want %q", tt.ref, got, tt.url)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// limits of `.play` programs run by `serve --play`
+const (
+	playMaxCodeSize   = 64 << 10
+	playMaxOutputSize = 1 << 20
+	playMemoryLimit   = 512 << 20
+	playBuildTimeout  = 60 * time.Second
+	playMaxRuns       = 4
+	playWaitDelay     = time.Second
+)
+
+// playRunTimeout is a variable for tests
+var playRunTimeout = 10 * time.Second
+
+// playSlots limits the number of programs built or run at the same time
+var playSlots = make(chan struct{}, playMaxRuns)
+
+// playEvent is a line of the response of `/_play/run`, the response is
+// streamed as newline delimited json
+type playEvent struct {
+	Kind string `json:"kind"` // stdout, stderr or exit
+	Body string `json:"body"`
+}
+
+// playStream writes events to the response and flushes them immediately
+type playStream struct {
+	sync.Mutex
+	w       http.ResponseWriter
+	enc     *json.Encoder
+	written int
+	cancel  context.CancelFunc
+}
+
+func (s *playStream) send(kind, body string) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.enc.Encode(playEvent{kind, body})
+
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// playWriter is the stdout or stderr of a program, the program is killed
+// when the total output exceeds `playMaxOutputSize`
+type playWriter struct {
+	stream *playStream
+	kind   string
+}
+
+func (w *playWriter) Write(p []byte) (int, error) {
+	w.stream.Lock()
+	w.stream.written += len(p)
+	exceeded := w.stream.written > playMaxOutputSize
+	w.stream.Unlock()
+
+	if exceeded {
+		w.stream.cancel()
+		return 0, errors.New("output limit exceeded")
+	}
+
+	w.stream.send(w.kind, string(p))
+
+	return len(p), nil
+}
+
+// handlePlay compiles and runs the posted go program with the local
+// toolchain, only enabled by `serve --play`
+func handlePlay(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := checkPlayOrigin(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	code, err := ioutil.ReadAll(io.LimitReader(r.Body, playMaxCodeSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(code) > playMaxCodeSize {
+		http.Error(w, "code is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	select {
+	case playSlots <- struct{}{}:
+		defer func() { <-playSlots }()
+	default:
+		http.Error(w, "too many programs running", http.StatusTooManyRequests)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stream := &playStream{w: w, enc: json.NewEncoder(w), cancel: cancel}
+
+	if err := runPlay(ctx, code, stream); err != nil {
+		stream.send("exit", err.Error())
+		return
+	}
+
+	stream.send("exit", "")
+}
+
+// checkPlayOrigin rejects requests from other sites, any page open in the
+// browser of the presenter could run code otherwise
+// the custom header makes cross site requests need a cors preflight, which
+// is never allowed, `Sec-Fetch-Site` and `Origin` are checked as well
+// the `Host` is checked against the address of the server, a page of another
+// domain which resolves to this machine (dns rebinding) is same origin with
+// the server and passes all the other checks
+func checkPlayOrigin(r *http.Request) error {
+	if r.Header.Get("X-Play") != "1" {
+		return errors.New("missing X-Play header")
+	}
+
+	if !playHostAllowed(r.Host) {
+		return errors.Errorf("unexpected host: %q", r.Host)
+	}
+
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" && site != "same-origin" {
+		return errors.New("cross site request")
+	}
+
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host != r.Host {
+			return errors.New("cross origin request")
+		}
+	}
+
+	return nil
+}
+
+// playHostAllowed reports whether host is the address the server listens on,
+// `localhost` or an ip, a dns rebinding attack always uses a domain name
+func playHostAllowed(host string) bool {
+	name, port, err := net.SplitHostPort(host)
+	if err != nil || port != strconv.Itoa(opts.port) {
+		return false
+	}
+
+	name = strings.ToLower(name)
+
+	return name == "localhost" ||
+		name == strings.ToLower(opts.host) ||
+		net.ParseIP(name) != nil
+}
+
+// playGoVersion returns the major and minor version of the local go
+// toolchain for the go directive of programs, without it the language
+// version is go1.16, e.g. no generics
+func playGoVersion() string {
+	playGoVersionOnce.Do(func() {
+		v := runtime.Version()
+		if out, err := exec.Command("go", "env", "GOVERSION").Output(); err == nil {
+			v = strings.TrimSpace(string(out))
+		}
+
+		// go1.21.3 -> 1.21, devel versions use the default
+		parts := strings.SplitN(strings.TrimPrefix(v, "go"), ".", 3)
+		if len(parts) < 2 {
+			return
+		}
+
+		minor := strings.IndexFunc(parts[1], func(r rune) bool { return r < '0' || r > '9' })
+		if minor >= 0 {
+			parts[1] = parts[1][:minor]
+		}
+
+		if _, err := strconv.Atoi(parts[0]); err != nil || parts[1] == "" {
+			return
+		}
+
+		playGoVersionValue = parts[0] + "." + parts[1]
+	})
+
+	return playGoVersionValue
+}
+
+var (
+	playGoVersionOnce  sync.Once
+	playGoVersionValue string
+)
+
+func runPlay(ctx context.Context, code []byte, stream *playStream) error {
+	dir, err := ioutil.TempDir("", "mypresent-play")
+	if err != nil {
+		return errors.Wrap(err, "could not create temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	goMod := "module play\n"
+	if v := playGoVersion(); v != "" {
+		goMod += "\ngo " + v + "\n"
+	}
+
+	files := map[string]string{
+		"go.mod":  goMod,
+		"main.go": string(code),
+	}
+
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return errors.Wrap(err, "could not write file")
+		}
+	}
+
+	bin := filepath.Join(dir, "prog")
+	if runtime.GOOS == "windows" {
+		bin += ".exe"
+	}
+
+	// build
+	buildCtx, cancel := context.WithTimeout(ctx, playBuildTimeout)
+	defer cancel()
+
+	build := exec.CommandContext(buildCtx, "go", "build", "-o", bin, ".")
+	build.Dir = dir
+	build.Env = append(os.Environ(), "GO111MODULE=on")
+	setProcessGroup(build)
+
+	if out, err := build.CombinedOutput(); err != nil {
+		if buildCtx.Err() == context.DeadlineExceeded {
+			return errors.New("build timed out")
+		}
+		stream.send("stderr", string(out))
+		return errors.New("build failed")
+	}
+
+	// run
+	runCtx, cancel := context.WithTimeout(ctx, playRunTimeout)
+	defer cancel()
+
+	// rlimits on the address space or data size break the go runtime,
+	// which reserves a lot of memory on start, the memory limit is a soft
+	// limit of the runtime, enforced by watching the resident memory
+	// the program runs in its own process group, so processes started by it
+	// are killed as well, `WaitDelay` stops waiting for the output of
+	// processes which survive anyway
+	run := exec.CommandContext(runCtx, bin)
+	run.Dir = dir
+	run.Env = append(os.Environ(), fmt.Sprintf("GOMEMLIMIT=%d", playMemoryLimit))
+	run.Stdout = &playWriter{stream, "stdout"}
+	run.Stderr = &playWriter{stream, "stderr"}
+	run.WaitDelay = playWaitDelay
+	setProcessGroup(run)
+
+	if err := run.Start(); err != nil {
+		return err
+	}
+
+	var memoryExceeded int32
+	watchCtx, stopWatch := context.WithCancel(runCtx)
+	go watchMemory(watchCtx, run.Process, &memoryExceeded)
+
+	err = run.Wait()
+	stopWatch()
+
+	// processes left behind by the program
+	killProcess(run.Process)
+
+	switch {
+	case runCtx.Err() == context.DeadlineExceeded:
+		return errors.Errorf("program timed out after %v", playRunTimeout)
+	case atomic.LoadInt32(&memoryExceeded) != 0:
+		return errors.New("program killed, memory limit exceeded")
+	case stream.written > playMaxOutputSize:
+		return errors.New("program killed, output limit exceeded")
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case err != nil:
+		return err
+	}
+
+	return nil
+}
+
+// playMemoryLimitSupported reports whether `watchMemory` can read the
+// resident memory of programs, only linux has /proc/<pid>/statm
+func playMemoryLimitSupported() bool {
+	return runtime.GOOS == "linux"
+}
+
+// watchMemory kills the process when its resident memory exceeds
+// `playMemoryLimit`, it only works where /proc is available
+func watchMemory(ctx context.Context, p *os.Process, exceeded *int32) {
+	if !playMemoryLimitSupported() {
+		return
+	}
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		buf, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/statm", p.Pid))
+		if err != nil {
+			return
+		}
+
+		fields := strings.Fields(string(buf))
+		if len(fields) < 2 {
+			return
+		}
+
+		pages, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return
+		}
+
+		if pages*int64(os.Getpagesize()) > playMemoryLimit {
+			atomic.StoreInt32(exceeded, 1)
+			killProcess(p)
+			return
+		}
+	}
+}
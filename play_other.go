@@ -0,0 +1,16 @@
+//go:build !unix
+
+package main
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is a no-op, process groups are only supported on unix
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcess kills only p itself, processes started by it survive
+func killProcess(p *os.Process) error {
+	return p.Kill()
+}
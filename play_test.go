@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckPlayOrigin(t *testing.T) {
+	defer func(host string, port int) { opts.host, opts.port = host, port }(opts.host, opts.port)
+	opts.host, opts.port = "slides.lan", 3999
+
+	tests := []struct {
+		host    string
+		headers map[string]string
+		allowed bool
+	}{
+		{"127.0.0.1:3999", map[string]string{"X-Play": "1"}, true},
+		{"localhost:3999", map[string]string{"X-Play": "1", "Origin": "http://localhost:3999"}, true},
+		{"[::1]:3999", map[string]string{"X-Play": "1", "Sec-Fetch-Site": "same-origin"}, true},
+		{"192.168.1.2:3999", map[string]string{"X-Play": "1"}, true},
+		{"Slides.LAN:3999", map[string]string{"X-Play": "1"}, true},
+		{"127.0.0.1:3999", nil, false},
+		{"127.0.0.1:3999", map[string]string{"X-Play": "1", "Sec-Fetch-Site": "cross-site"}, false},
+		{"127.0.0.1:3999", map[string]string{"X-Play": "1", "Origin": "http://evil.com"}, false},
+		{"127.0.0.1:8080", map[string]string{"X-Play": "1"}, false},
+		{"127.0.0.1", map[string]string{"X-Play": "1"}, false},
+		// dns rebinding, the page is same origin with the server
+		{"evil.com:3999", map[string]string{"X-Play": "1", "Origin": "http://evil.com:3999", "Sec-Fetch-Site": "same-origin"}, false},
+		{"localhost.evil.com:3999", map[string]string{"X-Play": "1"}, false},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/_play/run", nil)
+		r.Host = tt.host
+		for k, v := range tt.headers {
+			r.Header.Set(k, v)
+		}
+
+		if err := checkPlayOrigin(r); (err == nil) != tt.allowed {
+			t.Errorf("host %q, headers %v: got error %v; want allowed %v", tt.host, tt.headers, err, tt.allowed)
+		}
+	}
+}
+
+// testRunPlay runs code and returns the events sent to the client
+func testRunPlay(t *testing.T, code string) ([]playEvent, error) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not found")
+	}
+
+	w := httptest.NewRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := &playStream{w: w, enc: json.NewEncoder(w), cancel: cancel}
+	err := runPlay(ctx, []byte(code), stream)
+
+	var events []playEvent
+	dec := json.NewDecoder(w.Body)
+	for dec.More() {
+		var e playEvent
+		if err := dec.Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, e)
+	}
+
+	return events, err
+}
+
+func TestRunPlay(t *testing.T) {
+	events, err := testRunPlay(t, `package main
+
+import "fmt"
+
+func main() { fmt.Println("hello") }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 1 || events[0] != (playEvent{"stdout", "hello\n"}) {
+		t.Errorf("got events %v", events)
+	}
+}
+
+func TestRunPlayTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process groups are not supported")
+	}
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not found")
+	}
+
+	defer func(d time.Duration) { playRunTimeout = d }(playRunTimeout)
+	playRunTimeout = 500 * time.Millisecond
+
+	// the child holds the stdout pipe after the program is killed
+	start := time.Now()
+	_, err := testRunPlay(t, `package main
+
+import (
+	"os"
+	"os/exec"
+	"time"
+)
+
+func main() {
+	cmd := exec.Command("sleep", "60")
+	cmd.Stdout = os.Stdout
+	cmd.Start()
+	time.Sleep(time.Minute)
+}
+`)
+
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("got error %v; want timeout", err)
+	}
+
+	// the build is not limited by playRunTimeout
+	if d := time.Since(start); d > playBuildTimeout+10*time.Second {
+		t.Errorf("run took %v", d)
+	}
+}
+
+func TestRunPlayOutputLimit(t *testing.T) {
+	events, err := testRunPlay(t, `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func main() {
+	line := strings.Repeat("x", 1023)
+	for {
+		fmt.Println(line)
+	}
+}
+`)
+
+	if err == nil || !strings.Contains(err.Error(), "output limit exceeded") {
+		t.Errorf("got error %v; want output limit", err)
+	}
+
+	total := 0
+	for _, e := range events {
+		total += len(e.Body)
+	}
+	if total > playMaxOutputSize {
+		t.Errorf("got %d bytes of output; want at most %d", total, playMaxOutputSize)
+	}
+}
@@ -6,6 +6,10 @@ package present
 
 import (
 	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"regexp"
 	"strconv"
 	"unicode/utf8"
@@ -41,10 +45,18 @@ func addrToByteRange(addr string, start int, data []byte) (lo, hi int, err error
 		case ',':
 			if len(addr) == 1 {
 				hi = len(data)
-			} else {
-				_, hi, err = addrToByteRange(addr[1:], hi, data)
+				return
 			}
-			return
+			var end int
+			_, end, err = addrToByteRange(addr[1:], hi, data)
+			if err == nil && end < lo {
+				// A search wrapped around the file.
+				err = fmt.Errorf("address %q ends before it starts, the search wrapped around", addr[1:])
+			}
+			if err != nil {
+				return 0, 0, err
+			}
+			return lo, end, nil
 
 		case '+', '-':
 			if prevc == '+' || prevc == '-' {
@@ -98,6 +110,11 @@ func addrToByteRange(addr string, start int, data []byte) (lo, hi int, err error
 			}
 			pattern := addr[1:i]
 			lo, hi, err = addrRegexp(data, lo, hi, dir, pattern)
+			// A backward search selects the match itself, a forward
+			// search keeps its direction and moves to the next line.
+			if dir == '-' {
+				dir = 0
+			}
 			prevc = c
 			addr = addr[j:]
 			continue
@@ -202,7 +219,7 @@ func addrNumber(data []byte, lo, hi int, dir byte, n int, charOffset bool) (int,
 
 // addrRegexp searches for pattern in the given direction starting at lo, hi.
 // The direction dir is '+' (search forward from hi) or '-' (search backward from lo).
-// Backward searches are unimplemented.
+// Like sam, searches wrap around the file when there is no match.
 func addrRegexp(data []byte, lo, hi int, dir byte, pattern string) (int, int, error) {
 	// We want ^ and $ to work as in sam/acme, so use ?m.
 	re, err := regexp.Compile("(?m:" + pattern + ")")
@@ -210,9 +227,7 @@ func addrRegexp(data []byte, lo, hi int, dir byte, pattern string) (int, int, er
 		return 0, 0, err
 	}
 	if dir == '-' {
-		// Could implement reverse search using binary search
-		// through file, but that seems like overkill.
-		return 0, 0, errors.New("reverse search not implemented")
+		return addrRegexpBackward(data, lo, re, pattern)
 	}
 	m := re.FindIndex(data[hi:])
 	if len(m) > 0 {
@@ -227,3 +242,111 @@ func addrRegexp(data []byte, lo, hi int, dir byte, pattern string) (int, int, er
 	}
 	return m[0], m[1], nil
 }
+
+// addrRegexpBackward returns the last match of re ending at or before lo.
+// If there is none, it wraps to the last match in data.
+func addrRegexpBackward(data []byte, lo int, re *regexp.Regexp, pattern string) (int, int, error) {
+	// Matches are searched in the whole data, so ^ and $ still
+	// refer to the lines of data.
+	all := re.FindAllIndex(data, -1)
+	if len(all) == 0 {
+		return 0, 0, errors.New("no match for " + pattern)
+	}
+	for i := len(all) - 1; i >= 0; i-- {
+		if m := all[i]; m[1] <= lo && m[0] < lo {
+			return m[0], m[1], nil
+		}
+	}
+	// No match.  Wrap to end of data.
+	m := all[len(all)-1]
+	return m[0], m[1], nil
+}
+
+// symbolAddrRE matches Go symbol addresses, e.g. "func:main",
+// "func:(*Server).Handle" or "type:Config".
+var symbolAddrRE = regexp.MustCompile(`^(func|type):(\S+)$`)
+
+// symbolToByteRange returns the byte range of the declaration of the Go
+// function, method or type name in data. The doc comment of the declaration
+// is included if doc is true.
+func symbolToByteRange(kind, name string, data []byte, doc bool) (lo, hi int, err error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", data, parser.ParseComments)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var node ast.Node
+	var comment *ast.CommentGroup
+
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if kind == "func" && funcName(d) == name {
+				node, comment = d, d.Doc
+			}
+		case *ast.GenDecl:
+			if kind != "type" || d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if ts.Name.Name != name {
+					continue
+				}
+				// Select the whole declaration unless it is grouped.
+				if d.Lparen.IsValid() {
+					node, comment = ts, ts.Doc
+				} else {
+					node, comment = d, d.Doc
+				}
+			}
+		}
+		if node != nil {
+			break
+		}
+	}
+
+	if node == nil {
+		return 0, 0, fmt.Errorf("no %s %s in file", kind, name)
+	}
+
+	start := node.Pos()
+	if doc && comment != nil {
+		start = comment.Pos()
+	}
+
+	return fset.Position(start).Offset, fset.Position(node.End()).Offset, nil
+}
+
+// funcName returns the name of a function as used in symbol addresses,
+// e.g. "main", "Server.Close" or "(*Server).Handle".
+func funcName(d *ast.FuncDecl) string {
+	if d.Recv == nil || len(d.Recv.List) == 0 {
+		return d.Name.Name
+	}
+
+	typ := d.Recv.List[0].Type
+	star := false
+	if s, ok := typ.(*ast.StarExpr); ok {
+		typ, star = s.X, true
+	}
+
+	// Drop type parameters of generic receivers.
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+
+	recv := "?"
+	if id, ok := typ.(*ast.Ident); ok {
+		recv = id.Name
+	}
+
+	if star {
+		return "(*" + recv + ")." + d.Name.Name
+	}
+	return recv + "." + d.Name.Name
+}
@@ -0,0 +1,233 @@
+package present
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	parsers[".asciicast"] = parseAsciicast
+}
+
+// Asciicast is a recorded terminal session in the asciicast v2 format,
+// replayed by the player of the slide. The final frame is rendered as
+// html, so the recording is shown without javascript and when printed.
+type Asciicast struct {
+	Width, Height int
+	Duration      float64 // seconds of playback, after speed
+	Initial       []string
+	Frames        []CastFrame
+	Final         []template.HTML
+}
+
+func (a Asciicast) TemplateName() string { return "asciicast" }
+
+// Data returns the data of the player, the html of the lines at the start
+// and the frames after it.
+func (a Asciicast) Data() interface{} {
+	return map[string]interface{}{
+		"initial": a.Initial,
+		"frames":  a.Frames,
+	}
+}
+
+// CastFrame is the lines changed at a time of the playback.
+type CastFrame struct {
+	Time  float64    `json:"t"` // seconds since the start
+	Lines []CastLine `json:"l"`
+}
+
+// CastLine is the html of line N of the screen.
+type CastLine struct {
+	N int    `json:"n"`
+	H string `json:"h"`
+}
+
+// castHeader is the first line of an asciicast v2 file.
+type castHeader struct {
+	Version       int     `json:"version"`
+	Width         int     `json:"width"`
+	Height        int     `json:"height"`
+	IdleTimeLimit float64 `json:"idle_time_limit"`
+}
+
+// castFrameInterval is the shortest time between two frames, changes
+// within it are merged into one frame.
+const castFrameInterval = 1.0 / 60
+
+var asciicastRE = regexp.MustCompile(`^\.asciicast\s+(\S+)(?:\s+(\S+))?(?:\s+(\S+))?$`)
+
+// parseAsciicast parses an asciicast present directive. Its syntax:
+// .asciicast <filename> [start] [speed]
+// Start is the time in seconds the playback starts at, speed is a
+// multiplier of the recorded speed, 1 by default.
+func parseAsciicast(ctx *Context, sourceFile string, sourceLine int, cmd string) (Elem, error) {
+	args := asciicastRE.FindStringSubmatch(strings.TrimSpace(cmd))
+	if args == nil {
+		return nil, fmt.Errorf("%s:%d: syntax error for .asciicast invocation", sourceFile, sourceLine)
+	}
+
+	start, speed := 0.0, 1.0
+	var err error
+	if args[2] != "" {
+		if start, err = strconv.ParseFloat(args[2], 64); err != nil || start < 0 {
+			return nil, fmt.Errorf("%s:%d: invalid start %q", sourceFile, sourceLine, args[2])
+		}
+	}
+	if args[3] != "" {
+		if speed, err = strconv.ParseFloat(args[3], 64); err != nil || speed <= 0 {
+			return nil, fmt.Errorf("%s:%d: invalid speed %q", sourceFile, sourceLine, args[3])
+		}
+	}
+
+	filename := filepath.Join(filepath.Dir(sourceFile), args[1])
+	data, err := ctx.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%d: %v", sourceFile, sourceLine, err)
+	}
+
+	a, err := replayCast(data, start, speed)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%d: %s: %v", sourceFile, sourceLine, args[1], err)
+	}
+	return a, nil
+}
+
+// replayCast replays the output events of an asciicast v2 recording.
+func replayCast(data []byte, start, speed float64) (Asciicast, error) {
+	s := bufio.NewScanner(bytes.NewReader(data))
+	s.Buffer(nil, 16<<20)
+
+	if !s.Scan() {
+		return Asciicast{}, fmt.Errorf("empty recording")
+	}
+	var h castHeader
+	if err := json.Unmarshal(s.Bytes(), &h); err != nil {
+		return Asciicast{}, fmt.Errorf("invalid header: %v", err)
+	}
+	if h.Version != 2 {
+		return Asciicast{}, fmt.Errorf("unsupported version %d, only version 2 is supported", h.Version)
+	}
+	if h.Width <= 0 || h.Height <= 0 {
+		return Asciicast{}, fmt.Errorf("invalid terminal size %dx%d", h.Width, h.Height)
+	}
+
+	t := newVT(h.Width, h.Height)
+	a := Asciicast{Width: h.Width, Height: h.Height}
+
+	screen := func() []string {
+		lines := make([]string, h.Height)
+		for y := range lines {
+			lines[y] = t.lineHTML(y, true)
+		}
+		return lines
+	}
+
+	var last, now float64
+	started := false
+	cursorX, cursorY := 0, 0
+	for n := 2; s.Scan(); n++ {
+		line := bytes.TrimSpace(s.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		// An event is [time, type, data].
+		var e []interface{}
+		if err := json.Unmarshal(line, &e); err != nil || len(e) != 3 {
+			return Asciicast{}, fmt.Errorf("line %d: invalid event", n)
+		}
+		at, ok1 := e[0].(float64)
+		typ, ok2 := e[1].(string)
+		text, ok3 := e[2].(string)
+		if !ok1 || !ok2 || !ok3 {
+			return Asciicast{}, fmt.Errorf("line %d: invalid event", n)
+		}
+
+		// Pauses longer than the idle time limit are shortened.
+		gap := at - last
+		if h.IdleTimeLimit > 0 && gap > h.IdleTimeLimit {
+			gap = h.IdleTimeLimit
+		}
+		if gap > 0 {
+			now += gap
+		}
+		last = at
+
+		if typ != "o" {
+			continue
+		}
+
+		if !started && now >= start {
+			started = true
+			a.Initial = screen()
+			t.takeDirty()
+			cursorX, cursorY = t.x, t.y
+		}
+
+		t.Write(text)
+
+		if !started {
+			continue
+		}
+
+		// The lines of the old and new cursor position change too.
+		if t.x != cursorX || t.y != cursorY {
+			t.touch(cursorY)
+			t.touch(t.y)
+			cursorX, cursorY = t.x, t.y
+		}
+
+		var lines []CastLine
+		for _, y := range t.takeDirty() {
+			lines = append(lines, CastLine{y, t.lineHTML(y, true)})
+		}
+		a.addFrame((now-start)/speed, lines)
+	}
+	if err := s.Err(); err != nil {
+		return Asciicast{}, err
+	}
+
+	if !started {
+		a.Initial = screen()
+	}
+	if now > start {
+		a.Duration = (now - start) / speed
+	}
+	for y := 0; y < h.Height; y++ {
+		a.Final = append(a.Final, template.HTML(t.lineHTML(y, false)))
+	}
+	return a, nil
+}
+
+// addFrame adds the changed lines at time to the frames, merging them into
+// the last frame if it's less than castFrameInterval ago.
+func (a *Asciicast) addFrame(time float64, lines []CastLine) {
+	if len(lines) == 0 {
+		return
+	}
+
+	if n := len(a.Frames); n > 0 && time-a.Frames[n-1].Time < castFrameInterval {
+		f := &a.Frames[n-1]
+	merge:
+		for _, l := range lines {
+			for i := range f.Lines {
+				if f.Lines[i].N == l.N {
+					f.Lines[i] = l
+					continue merge
+				}
+			}
+			f.Lines = append(f.Lines, l)
+		}
+		return
+	}
+
+	a.Frames = append(a.Frames, CastFrame{time, lines})
+}
@@ -0,0 +1,49 @@
+package present
+
+import (
+	"html/template"
+	"reflect"
+	"testing"
+)
+
+func TestReplayCast(t *testing.T) {
+	cast := `{"version": 2, "width": 10, "height": 3, "idle_time_limit": 1}
+[0.5, "o", "$ ls\r\n"]
+[0.6, "i", "x"]
+[5.0, "o", "\u001b[31ma\u001b[0m <b>\r\n"]
+[5.1, "o", "$ \u001b[2J\u001b[Hdone"]
+`
+
+	a, err := replayCast([]byte(cast), 0.5, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantFinal := []template.HTML{"done", "", ""}
+	if !reflect.DeepEqual(a.Final, wantFinal) {
+		t.Errorf("got final frame %q; want %q", a.Final, wantFinal)
+	}
+
+	// The pause of 4.4 seconds is limited to 1 second.
+	if d := a.Duration - 0.6; d < -1e-9 || d > 1e-9 {
+		t.Errorf("got duration %v; want 0.6", a.Duration)
+	}
+
+	if len(a.Frames) != 3 {
+		t.Fatalf("got %d frames; want 3", len(a.Frames))
+	}
+	a.Frames[1].Time = float64(int(a.Frames[1].Time*1000+0.5)) / 1000
+	want := CastFrame{Time: 0.55, Lines: []CastLine{
+		{1, `<span class="ac-fg-1">a</span> &lt;b&gt;`},
+		{2, `<span class="ac-cursor"> </span>`},
+	}}
+	if !reflect.DeepEqual(a.Frames[1], want) {
+		t.Errorf("got frame %#v; want %#v", a.Frames[1], want)
+	}
+
+	for _, cast := range []string{"", `{"version": 1}`, `{"version": 2, "width": 80, "height": 24}` + "\n[1, 2]"} {
+		if _, err := replayCast([]byte(cast), 0, 1); err == nil {
+			t.Errorf("expected error for %q", cast)
+		}
+	}
+}
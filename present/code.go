@@ -12,9 +12,21 @@ import (
 )
 
 type Code struct {
-	Text template.HTML
-	Ext  string
-	raw  []byte // for test
+	Text     template.HTML
+	Ext      string // file extension, e.g. ".go"
+	Lang     string // language of the code for highlighting, e.g. "go"
+	FileName string
+	Rev      string // revision of the file, e.g. "v1.0", empty for the working tree
+	Play     bool   // runnable code
+	Raw      []byte // code without formatting
+	Callouts []Callout
+}
+
+// Callout is the annotation of callout N of a code block, given by a
+// "<N> text" line following the directive.
+type Callout struct {
+	N    int
+	Text string
 }
 
 func (c Code) TemplateName() string { return "code" }
@@ -25,11 +37,44 @@ func (c Code) TemplateName() string { return "code" }
 var (
 	highlightRE = regexp.MustCompile(`\s+HL([a-zA-Z0-9_]+)?$`)
 	hlCommentRE = regexp.MustCompile(`(.+) // HL(.*)$`)
-	codeRE      = regexp.MustCompile(`\.code\s+((?:(?:-numbers)\s+)*)([^\s]+)(?:\s+(.*))?$`)
+	codeRE      = regexp.MustCompile(`\.(code|play)\s+((?:(?:-edit|-numbers|-doc|-(?:lang|hl|steps|callouts|elide|tabwidth)\s+\S+)\s+)*)([^\s]+)(?:\s+(.*))?$`)
+	langFlagRE  = regexp.MustCompile(`-lang\s+(\S+)`)
+	hlFlagRE    = regexp.MustCompile(`-hl\s+(\S+)`)
+	stepsFlagRE = regexp.MustCompile(`-steps\s+(\S+)`)
+
+	// Callouts are marked by comments like "// <1>" or "# <1> <2>" at
+	// the end of a line, or given by -callouts.
+	calloutsFlagRE   = regexp.MustCompile(`-callouts\s+(\S+)`)
+	calloutCommentRE = regexp.MustCompile(`^(.*?)\s*(?://|#|--)\s*((?:<\d+>\s*)+)$`)
+	calloutMarkRE    = regexp.MustCompile(`<(\d+)>`)
+	calloutTextRE    = regexp.MustCompile(`^<(\d+)>\s+(.*)$`)
+
+	// Elided lines are marked by "// ELIDE" and "// END ELIDE" lines,
+	// or given by -elide.
+	elideFlagRE  = regexp.MustCompile(`-elide\s+(\S+)`)
+	elideStartRE = regexp.MustCompile(`(?://|#|--)\s*ELIDE$`)
+	elideEndRE   = regexp.MustCompile(`(?://|#|--)\s*END ELIDE$`)
+
+	// Regions are marked by "// START name OMIT" and "// END name OMIT" lines.
+	regionNameRE  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+	regionStartRE = regexp.MustCompile(`\bSTART\s+(\S+)\s+OMIT$`)
+	regionEndRE   = regexp.MustCompile(`\bEND\s+(\S+)\s+OMIT$`)
 )
 
 // parseCode parses a code present directive. Its syntax:
-// .code [-numbers] <filename> [address] [highlight]
+// .code [-numbers] [-edit] [-doc] [-lang <language>] [-hl <lines>] [-steps <lines>|<lines>...] [-callouts <lines>] [-elide <lines>] [-tabwidth <n>] <filename>[@rev] [address|region|symbol] [highlight]
+// The file is read as of rev if given, e.g. "main.go@v1.0".
+// A region selects the lines between its START and END markers.
+// A symbol selects a Go declaration, e.g. "func:(*Server).Handle" or "type:Config",
+// -doc includes its doc comment.
+// The language defaults to the file extension, e.g. "go" for main.go.
+// Lines of -hl and -steps are ranges relative to the selected code, e.g. "3-5,9".
+// Each group of -steps is highlighted in turn as the presenter advances.
+// Lines of -callouts get the callouts 1, 2, ... in order.
+// Lines of -elide, and lines between ELIDE and END ELIDE markers, are
+// collapsed into a placeholder line.
+// Tabs are expanded to -tabwidth, the .tabwidth of the document or 4, and
+// the common indentation of the lines is removed.
 func parseCode(ctx *Context, sourceFile string, sourceLine int, cmd string) (Elem, error) {
 	cmd = strings.TrimSpace(cmd)
 
@@ -46,57 +91,88 @@ func parseCode(ctx *Context, sourceFile string, sourceLine int, cmd string) (Ele
 	// Parse the remaining command line.
 	// Arguments:
 	// args[0]: whole match
-	// args[1]: flags ("-edit -numbers")
-	// args[2]: file name
-	// args[3]: optional address
+	// args[1]: command ("code" or "play")
+	// args[2]: flags ("-edit -numbers")
+	// args[3]: file name
+	// args[4]: optional address
 	args := codeRE.FindStringSubmatch(cmd)
-	if len(args) != 4 {
+	if len(args) != 5 {
 		return nil, fmt.Errorf("%s:%d: syntax error for .code invocation", sourceFile, sourceLine)
 	}
-	flags, file, addr := args[1], args[2], strings.TrimSpace(args[3])
+	command, flags, file, addr := args[1], args[2], args[3], strings.TrimSpace(args[4])
+	play := command == "play"
 
-	// Read in code file and (optionally) match address.
-	filename := filepath.Join(filepath.Dir(sourceFile), file)
-	textBytes, err := ctx.ReadFile(filename)
+	file, rev := splitRev(file)
+
+	ext := filepath.Ext(file)
+	lang := strings.TrimPrefix(ext, ".")
+	if m := langFlagRE.FindStringSubmatch(flags); m != nil {
+		lang = m[1]
+	}
 
+	lines, prefix, suffix, err := readCodeLines(ctx, sourceFile, file, rev, addr, strings.Contains(flags, "-doc"))
 	if err != nil {
 		return nil, fmt.Errorf("%s:%d: %v", sourceFile, sourceLine, err)
 	}
 
-	lo, hi, err := addrToByteRange(addr, 0, textBytes)
+	tabWidth, err := ctx.tabWidthFlag(flags)
 	if err != nil {
 		return nil, fmt.Errorf("%s:%d: %v", sourceFile, sourceLine, err)
 	}
-	if lo > hi {
-		// The search in addrToByteRange can wrap around so we might
-		// end up with the range ending before its starting point
-		hi, lo = lo, hi
+
+	data := &codeTemplateData{
+		Lines:   highlightCode(dedentLines(formatLines(lines, highlight, tabWidth)), lang),
+		Edit:    strings.Contains(flags, "-edit"),
+		Numbers: strings.Contains(flags, "-numbers"),
 	}
 
-	// Acme pattern matches can stop mid-line,
-	// so run to end of line in both directions if not at line start/end.
-	for lo > 0 && textBytes[lo-1] != '\n' {
-		lo--
+	// The whole program is needed to run the code, the text around
+	// the selected lines is hidden.
+	if play {
+		data.Prefix, data.Suffix = prefix, suffix
 	}
-	if hi > 0 {
-		for hi < len(textBytes) && textBytes[hi-1] != '\n' {
-			hi++
+
+	if m := hlFlagRE.FindStringSubmatch(flags); m != nil {
+		hl, err := parseLineRanges(m[1], len(lines))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: bad -hl lines: %v", sourceFile, sourceLine, err)
+		}
+		for _, i := range hl {
+			data.Lines[i-1].HL = true
 		}
 	}
 
-	lines := codeLines(textBytes, lo, hi)
-
-	var b strings.Builder
+	if m := stepsFlagRE.FindStringSubmatch(flags); m != nil {
+		for step, group := range strings.Split(m[1], "|") {
+			hl, err := parseLineRanges(group, len(lines))
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: bad -steps lines: %v", sourceFile, sourceLine, err)
+			}
+			for _, i := range hl {
+				data.Lines[i-1].Steps = append(data.Lines[i-1].Steps, step+1)
+			}
+			data.Steps = step + 1
+		}
+	}
 
-	for _, line := range lines {
-		b.WriteString(line.L)
-		b.WriteRune('\n')
+	if m := calloutsFlagRE.FindStringSubmatch(flags); m != nil {
+		callouts, err := parseLineRanges(m[1], len(lines))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: bad -callouts lines: %v", sourceFile, sourceLine, err)
+		}
+		for n, i := range callouts {
+			data.Lines[i-1].Callouts = append(data.Lines[i-1].Callouts, n+1)
+		}
 	}
 
-	data := &codeTemplateData{
-		Lines:   formatLines(lines, highlight),
-		Edit:    strings.Contains(flags, "-edit"),
-		Numbers: strings.Contains(flags, "-numbers"),
+	var elide []int
+	if m := elideFlagRE.FindStringSubmatch(flags); m != nil {
+		if elide, err = parseLineRanges(m[1], len(lines)); err != nil {
+			return nil, fmt.Errorf("%s:%d: bad -elide lines: %v", sourceFile, sourceLine, err)
+		}
+	}
+	if data.Lines, err = elideLines(data.Lines, elide); err != nil {
+		return nil, fmt.Errorf("%s:%d: %v", sourceFile, sourceLine, err)
 	}
 
 	var buf bytes.Buffer
@@ -104,19 +180,35 @@ func parseCode(ctx *Context, sourceFile string, sourceLine int, cmd string) (Ele
 		return nil, err
 	}
 	return Code{
-		Text: template.HTML(b.String()),
-		Ext:  ".go",
-		raw:  rawCode(lines),
+		Text:     template.HTML(buf.String()),
+		Ext:      ext,
+		Lang:     lang,
+		FileName: file,
+		Rev:      rev,
+		Play:     play,
+		Raw:      rawCode(lines),
 	}, nil
 }
 
 // formatLines returns a new slice of codeLine with the given lines
-// replacing tabs with spaces and adding highlighting where needed.
-func formatLines(lines []codeLine, highlight string) []codeLine {
+// replacing tabs with spaces, trimming trailing white space and adding
+// highlighting and callouts where needed.
+func formatLines(lines []codeLine, highlight string, tabWidth int) []codeLine {
 	formatted := make([]codeLine, len(lines))
 	for i, line := range lines {
 		// Replace tabs with spaces, which work better in HTML.
-		line.L = strings.Replace(line.L, "\t", "    ", -1)
+		line.L = expandTabs(line.L, tabWidth)
+
+		// Add callouts of lines that end with "// <N>"
+		// and strip the markers.
+		if m := calloutCommentRE.FindStringSubmatch(line.L); m != nil {
+			line.L = m[1]
+			line.Callouts = nil
+			for _, c := range calloutMarkRE.FindAllStringSubmatch(m[2], -1) {
+				n, _ := strconv.Atoi(c[1])
+				line.Callouts = append(line.Callouts, n)
+			}
+		}
 
 		// Highlight lines that end with "// HL[highlight]"
 		// and strip the magic comment.
@@ -125,11 +217,161 @@ func formatLines(lines []codeLine, highlight string) []codeLine {
 			line.HL = m[2] == highlight
 		}
 
+		line.L = strings.TrimRight(line.L, " \t\r")
+
 		formatted[i] = line
 	}
 	return formatted
 }
 
+// dedentLines removes the common indentation of the lines, e.g. of a
+// method or a nested block.
+func dedentLines(lines []codeLine) []codeLine {
+	s := make([]string, len(lines))
+	for i, line := range lines {
+		s[i] = line.L
+	}
+	indent := commonIndent(s)
+	for i := range lines {
+		if len(lines[i].L) >= indent {
+			lines[i].L = lines[i].L[indent:]
+		}
+	}
+	return lines
+}
+
+// readCodeLines reads file, relative to sourceFile and as of rev if not
+// empty, and returns the lines selected by addr, which is an address,
+// a region or a Go symbol. The doc comment of a symbol is included if doc
+// is true. Prefix and suffix are the text of the file before and after
+// the selected lines.
+func readCodeLines(ctx *Context, sourceFile, file, rev, addr string, doc bool) (lines []codeLine, prefix, suffix []byte, err error) {
+	filename := filepath.Join(filepath.Dir(sourceFile), file)
+	textBytes, err := ctx.readFileAt(filename, rev)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var lo, hi int
+	if m := symbolAddrRE.FindStringSubmatch(addr); m != nil {
+		lo, hi, err = symbolToByteRange(m[1], m[2], textBytes, doc)
+	} else if regionNameRE.MatchString(addr) {
+		lo, hi, err = regionToByteRange(addr, textBytes)
+	} else {
+		lo, hi, err = addrToByteRange(addr, 0, textBytes)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Acme pattern matches can stop mid-line,
+	// so run to end of line in both directions if not at line start/end.
+	for lo > 0 && textBytes[lo-1] != '\n' {
+		lo--
+	}
+	if hi > 0 {
+		for hi < len(textBytes) && textBytes[hi-1] != '\n' {
+			hi++
+		}
+	}
+
+	return codeLines(textBytes, lo, hi), textBytes[:lo], textBytes[hi:], nil
+}
+
+// splitRev splits a file argument like "main.go@v1.0" into the file name
+// and the revision.
+func splitRev(file string) (name, rev string) {
+	if i := strings.LastIndex(file, "@"); i > 0 && i < len(file)-1 {
+		return file[:i], file[i+1:]
+	}
+	return file, ""
+}
+
+// parseLineRanges parses comma separated line numbers and ranges,
+// e.g. "3-5,9", of code with n lines.
+func parseLineRanges(s string, n int) ([]int, error) {
+	var result []int
+	for _, r := range strings.Split(s, ",") {
+		lo, hi := r, r
+		if i := strings.Index(r, "-"); i >= 0 {
+			lo, hi = r[:i], r[i+1:]
+		}
+		l, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid range %q", r)
+		}
+		h, err := strconv.Atoi(hi)
+		if err != nil {
+			return nil, fmt.Errorf("invalid range %q", r)
+		}
+		if l < 1 || h > n || l > h {
+			return nil, fmt.Errorf("range %q out of 1-%d", r, n)
+		}
+		for i := l; i <= h; i++ {
+			result = append(result, i)
+		}
+	}
+	return result, nil
+}
+
+// elideLines collapses the given lines, 1-based, and the lines between
+// ELIDE and END ELIDE markers, including the markers, into placeholder
+// lines. Other lines keep their numbers.
+func elideLines(lines []codeLine, elide []int) ([]codeLine, error) {
+	elided := make([]bool, len(lines))
+	for _, i := range elide {
+		elided[i-1] = true
+	}
+
+	start := -1
+	for i, line := range lines {
+		switch {
+		case elideEndRE.MatchString(line.L):
+			if start < 0 {
+				return nil, fmt.Errorf("END ELIDE at line %d without ELIDE", line.N)
+			}
+			for j := start; j <= i; j++ {
+				elided[j] = true
+			}
+			start = -1
+		case elideStartRE.MatchString(line.L) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		return nil, fmt.Errorf("ELIDE at line %d has no END ELIDE", lines[start].N)
+	}
+
+	var result []codeLine
+	for i, line := range lines {
+		if !elided[i] {
+			result = append(result, line)
+			continue
+		}
+		if i == 0 || !elided[i-1] {
+			result = append(result, codeLine{})
+		}
+		p := &result[len(result)-1]
+		p.Elided = append(p.Elided, line)
+	}
+	return result, nil
+}
+
+// highlightCode sets the syntax highlighted HTML of the given lines.
+func highlightCode(lines []codeLine, lang string) []codeLine {
+	if len(lines) == 0 {
+		return lines
+	}
+	s := make([]string, len(lines))
+	for i, line := range lines {
+		s[i] = line.L
+	}
+	for i, h := range highlightLines(strings.Join(s, "\n"), lang) {
+		lines[i].H = h
+	}
+	return lines
+}
+
 // rawCode returns the code represented by the given codeLines without any kind
 // of formatting.
 func rawCode(lines []codeLine) []byte {
@@ -145,33 +387,123 @@ type codeTemplateData struct {
 	Lines          []codeLine
 	Prefix, Suffix []byte
 	Edit, Numbers  bool
+	Steps          int // number of highlight steps
 }
 
 var leadingSpaceRE = regexp.MustCompile(`^[ \t]*`)
 
+// trimHTML trims the surrounding white space of highlighted code,
+// which is never inside a tag.
+func trimHTML(h template.HTML) template.HTML {
+	return template.HTML(strings.TrimSpace(string(h)))
+}
+
+// joinSteps formats steps as a space separated list.
+func joinSteps(steps []int) string {
+	s := make([]string, len(steps))
+	for i, step := range steps {
+		s[i] = strconv.Itoa(step)
+	}
+	return strings.Join(s, " ")
+}
+
 var codeTemplate = template.Must(template.New("code").Funcs(template.FuncMap{
-	"trimSpace":    strings.TrimSpace,
+	"trimHTML":     trimHTML,
+	"joinSteps":    joinSteps,
 	"leadingSpace": leadingSpaceRE.FindString,
 }).Parse(codeTemplateHTML))
 
 const codeTemplateHTML = `
 {{with .Prefix}}<pre style="display: none"><span>{{printf "%s" .}}</span></pre>{{end}}
 
-<pre{{if .Edit}} contenteditable="true" spellcheck="false"{{end}}{{if .Numbers}} class="numbers"{{end}}>{{/*
-	*/}}{{range .Lines}}<span num="{{.N}}">{{/*
-	*/}}{{if .HL}}{{leadingSpace .L}}<b>{{trimSpace .L}}</b>{{/*
-	*/}}{{else}}{{.L}}{{end}}{{/*
-*/}}</span>
+<pre{{if .Edit}} contenteditable="true" spellcheck="false"{{end}}{{if .Numbers}} class="numbers"{{end}}{{/*
+	*/}}{{with .Steps}} data-steps="{{.}}"{{end}}>{{/*
+	*/}}{{range .Lines}}{{if .Elided}}{{template "elided" .Elided}}{{else}}{{/*
+	*/}}<span num="{{.N}}"{{with .Steps}} data-steps="{{joinSteps .}}"{{end}}>{{/*
+	*/}}{{if .HL}}{{leadingSpace .L}}<b>{{trimHTML .H}}</b>{{/*
+	*/}}{{else}}{{.H}}{{end}}{{/*
+	*/}}{{range .Callouts}} <span class="callout" data-callout="{{.}}"></span>{{end}}{{/*
+*/}}</span>{{end}}
 {{end}}</pre>
 
 {{with .Suffix}}<pre style="display: none"><span>{{printf "%s" .}}</span></pre>{{end}}
+
+{{define "elided"}}<span class="elided" data-lines="{{len .}}">{{/*
+	The elided code is hidden, but still part of the program of .play.
+	*/}}<span style="display: none">{{range $i, $l := .}}{{if $i}}
+{{end}}{{$l.L}}{{end}}</span></span>{{end}}
 `
 
 // codeLine represents a line of code extracted from a source file.
 type codeLine struct {
-	L  string // The line of code.
-	N  int    // The line number from the source file.
-	HL bool   // Whether the line should be highlighted.
+	L  string        // The line of code.
+	H  template.HTML // The syntax highlighted line of code.
+	N  int           // The line number from the source file.
+	HL bool          // Whether the line should be highlighted.
+
+	// Steps in which the line is highlighted.
+	Steps []int
+
+	// Callouts of the line.
+	Callouts []int
+
+	// Lines collapsed into this placeholder line, if any.
+	Elided []codeLine
+}
+
+// parseCallouts parses the "<N> text" lines following a code directive.
+func parseCallouts(lines *Lines) []Callout {
+	var callouts []Callout
+	for {
+		text, ok := lines.next()
+		m := calloutTextRE.FindStringSubmatch(text)
+		if !ok || m == nil {
+			lines.back()
+			return callouts
+		}
+		n, _ := strconv.Atoi(m[1])
+		callouts = append(callouts, Callout{N: n, Text: m[2]})
+	}
+}
+
+// regionToByteRange returns the byte range of the lines between the
+// START and END markers of the named region, excluding the markers.
+func regionToByteRange(name string, src []byte) (lo, hi int, err error) {
+	lo, hi = -1, -1
+	var names []string
+
+	for start := 0; start < len(src); {
+		end := bytes.IndexByte(src[start:], '\n') + 1
+		if end == 0 {
+			end = len(src) - start
+		}
+		line := bytes.TrimRight(src[start:start+end], " \t\r\n")
+
+		if m := regionStartRE.FindSubmatch(line); m != nil {
+			names = append(names, string(m[1]))
+			if string(m[1]) == name && lo < 0 {
+				lo = start + end
+			}
+		} else if m := regionEndRE.FindSubmatch(line); m != nil {
+			if string(m[1]) == name && lo >= 0 && hi < 0 {
+				hi = start
+			}
+		}
+
+		start += end
+	}
+
+	if lo < 0 {
+		if len(names) == 0 {
+			return 0, 0, fmt.Errorf("region %q not found, no regions in file", name)
+		}
+		return 0, 0, fmt.Errorf("region %q not found, available regions: %s", name, strings.Join(names, ", "))
+	}
+	if hi < 0 {
+		return 0, 0, fmt.Errorf("region %q has no END marker", name)
+	}
+
+	return lo, hi, nil
 }
 
 // codeLines takes a source file and returns the lines that
@@ -23,12 +23,12 @@ func main() {
 `)
 
 	helloTestHTML := template.HTML(`
-<pre><span num="2">package main</span>
+<pre><span num="2"><span class="hljs-keyword">package</span> main</span>
 <span num="3"></span>
-<span num="4">import &#34;fmt&#34;</span>
+<span num="4"><span class="hljs-keyword">import</span> <span class="hljs-string">&#34;fmt&#34;</span></span>
 <span num="5"></span>
-<span num="6">func main() {</span>
-<span num="7">    fmt.Println(&#34;hello, test&#34;)</span>
+<span num="6"><span class="hljs-keyword">func</span> main() {</span>
+<span num="7">    fmt.Println(<span class="hljs-string">&#34;hello, test&#34;</span>)</span>
 <span num="8">}</span>
 </pre>
 `)
@@ -41,6 +41,34 @@ import "fmt" // HLimport
 func main() { // HLfunc
 	fmt.Println("hello, test") // HL
 }
+`)
+
+	helloTestRegions := []byte(`
+package main
+
+// START imports OMIT
+import "fmt"
+
+// END imports OMIT
+// START main OMIT
+func main() {
+	fmt.Println("hello, test")
+}
+// END main OMIT
+`)
+
+	serverTest := []byte(`package server
+
+type (
+	// Config configures a Server.
+	Config struct{ Addr string }
+)
+
+// Server serves requests.
+type Server struct{}
+
+// Handle handles a request.
+func (s *Server) Handle() {}
 `)
 
 	highlight := func(h template.HTML, s string) template.HTML {
@@ -92,7 +120,7 @@ func main() { // HLfunc
 				Ext:      ".go",
 				FileName: "main.go",
 				Raw:      helloTestHL,
-				Text:     highlight(helloTestHTML, "fmt.Println(&#34;hello, test&#34;)"),
+				Text:     highlight(helloTestHTML, `fmt.Println(<span class="hljs-string">&#34;hello, test&#34;</span>)`),
 			},
 		},
 		{
@@ -105,7 +133,7 @@ func main() { // HLfunc
 				FileName: "main.go",
 				Play:     false,
 				Raw:      []byte("package main\n\nimport \"fmt\" // HLimport\n\nfunc main() { // HLfunc\n\tfmt.Println(\"hello, test\") // HL\n}"),
-				Text:     highlight(helloTestHTML, "func main() {"),
+				Text:     highlight(helloTestHTML, `<span class="hljs-keyword">func</span> main() {`),
 			},
 		},
 		{
@@ -132,7 +160,10 @@ func main() { // HLfunc
 				FileName: "main.go",
 				Play:     false,
 				Raw:      []byte("func main() {\n\tfmt.Println(\"hello, test\")\n}"),
-				Text:     "<pre><span num=\"6\">func main() {</span>\n<span num=\"7\">    fmt.Println(&#34;hello, test&#34;)</span>\n<span num=\"8\">}</span>\n</pre>",
+				Text: `<pre><span num="6"><span class="hljs-keyword">func</span> main() {</span>
+<span num="7">    fmt.Println(<span class="hljs-string">&#34;hello, test&#34;</span>)</span>
+<span num="8">}</span>
+</pre>`,
 			},
 		},
 		{
@@ -145,7 +176,8 @@ func main() { // HLfunc
 				FileName: "main.go",
 				Play:     false,
 				Raw:      []byte("func main() {"),
-				Text:     "<pre><span num=\"6\">func main() {</span>\n</pre>",
+				Text: `<pre><span num="6"><span class="hljs-keyword">func</span> main() {</span>
+</pre>`,
 			},
 		},
 		{
@@ -155,6 +187,85 @@ func main() { // HLfunc
 			cmd:        ".code main.go /function main/",
 			err:        "main.go:0: no match for function main",
 		},
+		{
+			name:       "back to func main",
+			readFile:   read(helloTest, nil),
+			sourceFile: "main.go",
+			cmd:        ".code main.go /Println/-/func main/,/^}/",
+			Code: Code{
+				Ext:      ".go",
+				FileName: "main.go",
+				Raw:      []byte("func main() {\n\tfmt.Println(\"hello, test\")\n}"),
+				Text: `<pre><span num="6"><span class="hljs-keyword">func</span> main() {</span>
+<span num="7">    fmt.Println(<span class="hljs-string">&#34;hello, test&#34;</span>)</span>
+<span num="8">}</span>
+</pre>`,
+			},
+		},
+		{
+			name:       "from doc comment to the end of func",
+			readFile:   read(serverTest, nil),
+			sourceFile: "server.go",
+			cmd:        `.code server.go /func.*Handle/-/^\/\//,/^}|{}$/`,
+			Code: Code{
+				Ext:      ".go",
+				FileName: "server.go",
+				Raw:      []byte("// Handle handles a request.\nfunc (s *Server) Handle() {}"),
+				Text: `<pre><span num="11"><span class="hljs-comment">// Handle handles a request.</span></span>
+<span num="12"><span class="hljs-keyword">func</span> (s *Server) Handle() {}</span>
+</pre>`,
+			},
+		},
+		{
+			name:       "line after match",
+			readFile:   read(helloTest, nil),
+			sourceFile: "main.go",
+			cmd:        ".code main.go /func main/+1",
+			Code: Code{
+				Ext:      ".go",
+				FileName: "main.go",
+				Raw:      []byte("\tfmt.Println(\"hello, test\")"),
+				Text:     `<pre><span num="7">fmt.Println(<span class="hljs-string">&#34;hello, test&#34;</span>)</span>` + "\n</pre>",
+			},
+		},
+		{
+			name:       "line after forward search",
+			readFile:   read(helloTest, nil),
+			sourceFile: "main.go",
+			cmd:        ".code main.go /import/+/func/",
+			Code: Code{
+				Ext:      ".go",
+				FileName: "main.go",
+				Raw:      []byte("\tfmt.Println(\"hello, test\")"),
+				Text:     `<pre><span num="7">fmt.Println(<span class="hljs-string">&#34;hello, test&#34;</span>)</span>` + "\n</pre>",
+			},
+		},
+		{
+			name:       "reverse search wraps around",
+			readFile:   read(helloTest, nil),
+			sourceFile: "main.go",
+			cmd:        ".code main.go /import/-/}/",
+			Code: Code{
+				Ext:      ".go",
+				FileName: "main.go",
+				Raw:      []byte("}"),
+				Text:     `<pre><span num="8">}</span>` + "\n</pre>",
+			},
+		},
+		{
+			name:       "range wraps around",
+			readFile:   read(helloTest, nil),
+			sourceFile: "main.go",
+			cmd:        ".code main.go /func main/,/import/",
+			err:        `address "/import/" ends before it starts, the search wrapped around`,
+		},
+		{
+			name:       "no match backward",
+			readFile:   read(helloTest, nil),
+			sourceFile: "main.go",
+			cmd:        ".code main.go /func main/-/nothing/",
+			err:        "no match for nothing",
+		},
 		{
 			name:       "all code with  numbers",
 			readFile:   read(helloTest, nil),
@@ -168,6 +279,110 @@ func main() { // HLfunc
 				Text: "<pre class=\"numbers\">" + helloTestHTML[6:],
 			},
 		},
+		{
+			name:       "highlight lines by range",
+			readFile:   read(helloTest, nil),
+			sourceFile: "main.go",
+			cmd:        ".code -hl 2 main.go /func main/,",
+			Code: Code{
+				Ext:      ".go",
+				FileName: "main.go",
+				Raw:      []byte("func main() {\n\tfmt.Println(\"hello, test\")\n}"),
+				Text: `<pre><span num="6"><span class="hljs-keyword">func</span> main() {</span>
+<span num="7">    <b>fmt.Println(<span class="hljs-string">&#34;hello, test&#34;</span>)</b></span>
+<span num="8">}</span>
+</pre>`,
+			},
+		},
+		{
+			name:       "highlight lines out of range",
+			readFile:   read(helloTest, nil),
+			sourceFile: "main.go",
+			cmd:        ".code -hl 2-4 main.go /func main/,",
+			err:        `bad -hl lines: range "2-4" out of 1-3`,
+		},
+		{
+			name:       "highlight steps",
+			readFile:   read(helloTest, nil),
+			sourceFile: "main.go",
+			cmd:        ".code -steps 1,3|2-3 main.go /func main/,",
+			Code: Code{
+				Ext:      ".go",
+				FileName: "main.go",
+				Raw:      []byte("func main() {\n\tfmt.Println(\"hello, test\")\n}"),
+				Text: `<pre data-steps="2"><span num="6" data-steps="1"><span class="hljs-keyword">func</span> main() {</span>
+<span num="7" data-steps="2">    fmt.Println(<span class="hljs-string">&#34;hello, test&#34;</span>)</span>
+<span num="8" data-steps="1 2">}</span>
+</pre>`,
+			},
+		},
+		{
+			name:       "named region",
+			readFile:   read(helloTestRegions, nil),
+			sourceFile: "main.go",
+			cmd:        ".code main.go main",
+			Code: Code{
+				Ext:      ".go",
+				FileName: "main.go",
+				Raw:      []byte("func main() {\n\tfmt.Println(\"hello, test\")\n}"),
+				Text: `<pre><span num="9"><span class="hljs-keyword">func</span> main() {</span>
+<span num="10">    fmt.Println(<span class="hljs-string">&#34;hello, test&#34;</span>)</span>
+<span num="11">}</span>
+</pre>`,
+			},
+		},
+		{
+			name:       "unknown region",
+			readFile:   read(helloTestRegions, nil),
+			sourceFile: "main.go",
+			cmd:        ".code main.go types",
+			err:        `region "types" not found, available regions: imports, main`,
+		},
+		{
+			name:       "go method",
+			readFile:   read(serverTest, nil),
+			sourceFile: "server.go",
+			cmd:        ".code server.go func:(*Server).Handle",
+			Code: Code{
+				Ext:      ".go",
+				FileName: "server.go",
+				Raw:      []byte("func (s *Server) Handle() {}"),
+				Text:     `<pre><span num="12"><span class="hljs-keyword">func</span> (s *Server) Handle() {}</span>` + "\n</pre>",
+			},
+		},
+		{
+			name:       "go type with doc comment",
+			readFile:   read(serverTest, nil),
+			sourceFile: "server.go",
+			cmd:        ".code -doc server.go type:Server",
+			Code: Code{
+				Ext:      ".go",
+				FileName: "server.go",
+				Raw:      []byte("// Server serves requests.\ntype Server struct{}"),
+				Text: `<pre><span num="8"><span class="hljs-comment">// Server serves requests.</span></span>
+<span num="9"><span class="hljs-keyword">type</span> Server <span class="hljs-keyword">struct</span>{}</span>
+</pre>`,
+			},
+		},
+		{
+			name:       "go grouped type",
+			readFile:   read(serverTest, nil),
+			sourceFile: "server.go",
+			cmd:        ".code server.go type:Config",
+			Code: Code{
+				Ext:      ".go",
+				FileName: "server.go",
+				Raw:      []byte("\tConfig struct{ Addr string }"),
+				Text:     `<pre><span num="5">Config <span class="hljs-keyword">struct</span>{ Addr <span class="hljs-type">string</span> }</span>` + "\n</pre>",
+			},
+		},
+		{
+			name:       "go symbol not found",
+			readFile:   read(serverTest, nil),
+			sourceFile: "server.go",
+			cmd:        ".code server.go func:Server.Handle",
+			err:        "no func Server.Handle in file",
+		},
 		{
 			name:       "all code editable",
 			readFile:   read(helloTest, nil),
@@ -186,7 +401,7 @@ func main() { // HLfunc
 	trimBytes := func(b []byte) string { return strings.TrimSpace(string(b)) }
 
 	for _, tt := range tests {
-		ctx := &Context{tt.readFile}
+		ctx := &Context{ReadFile: tt.readFile}
 		e, err := parseCode(ctx, tt.sourceFile, 0, tt.cmd)
 		if err != nil {
 			if tt.err == "" {
@@ -222,3 +437,147 @@ func main() { // HLfunc
 		}
 	}
 }
+
+func TestParseCodeRev(t *testing.T) {
+	ctx := &Context{
+		ReadFile: func(string) ([]byte, error) { return []byte("new\n"), nil },
+		ReadFileAt: func(filename, rev string) ([]byte, error) {
+			if rev != "v1" {
+				return nil, fmt.Errorf("unknown revision %s", rev)
+			}
+			return []byte("old\n"), nil
+		},
+	}
+
+	e, err := parseCode(ctx, "talk.slide", 1, ".code main.txt@v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := e.(Code)
+	if c.FileName != "main.txt" || c.Rev != "v1" || string(c.Raw) != "old\n" {
+		t.Errorf("got file %q, rev %q, raw %q", c.FileName, c.Rev, c.Raw)
+	}
+
+	if _, err := parseCode(ctx, "talk.slide", 1, ".code main.txt@v2"); err == nil || !strings.Contains(err.Error(), "unknown revision v2") {
+		t.Errorf("expected unknown revision error, got %v", err)
+	}
+
+	ctx.ReadFileAt = nil
+	if _, err := parseCode(ctx, "talk.slide", 1, ".code main.txt@v1"); err == nil || !strings.Contains(err.Error(), "revisions are not supported") {
+		t.Errorf("expected unsupported error, got %v", err)
+	}
+}
+
+func TestParseCallouts(t *testing.T) {
+	src := "a := open() // <1>\nb := 2\nclose(a) # <2> <3>\n"
+	ctx := &Context{ReadFile: func(string) ([]byte, error) { return []byte(src), nil }}
+
+	doc, err := ctx.Parse(strings.NewReader(`Title
+
+* Slide
+
+.code -callouts 2 main.txt
+<1> opens the *file*
+<2> closes it
+
+Text
+`), "talk.slide", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := doc.Sections[0].Elem[0].(Code)
+	want := `<pre><span num="1">a := open() <span class="callout" data-callout="1"></span></span>
+<span num="2">b := 2 <span class="callout" data-callout="1"></span></span>
+<span num="3">close(a) <span class="callout" data-callout="2"></span> <span class="callout" data-callout="3"></span></span>
+</pre>`
+	if got := strings.TrimSpace(string(c.Text)); got != want {
+		t.Errorf("got Text\n%s\nwant\n%s", got, want)
+	}
+	if got := string(c.Raw); got != src {
+		t.Errorf("got Raw %q; want %q", got, src)
+	}
+
+	callouts := []Callout{{1, "opens the *file*"}, {2, "closes it"}}
+	if fmt.Sprint(c.Callouts) != fmt.Sprint(callouts) {
+		t.Errorf("got Callouts %v; want %v", c.Callouts, callouts)
+	}
+	if n := len(doc.Sections[0].Elem); n != 2 {
+		t.Errorf("got %d elems; want the code and the text", n)
+	}
+}
+
+func TestParseCodeElide(t *testing.T) {
+	src := "1\n2\n// ELIDE\n4\n// END ELIDE\n6\n7\n8\n"
+	ctx := &Context{ReadFile: func(string) ([]byte, error) { return []byte(src), nil }}
+
+	e, err := parseCode(ctx, "talk.slide", 1, ".code -numbers -hl 6 -elide 7-8 main.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<pre class="numbers"><span num="1">1</span>
+<span num="2">2</span>
+<span class="elided" data-lines="3"><span style="display: none">// ELIDE
+4
+// END ELIDE</span></span>
+<span num="6"><b>6</b></span>
+<span class="elided" data-lines="2"><span style="display: none">7
+8</span></span>
+</pre>`
+	if got := strings.TrimSpace(string(e.(Code).Text)); got != want {
+		t.Errorf("got Text\n%s\nwant\n%s", got, want)
+	}
+
+	for _, src := range []string{"// ELIDE\n1\n", "1\n# END ELIDE\n"} {
+		ctx.ReadFile = func(string) ([]byte, error) { return []byte(src), nil }
+		if _, err := parseCode(ctx, "talk.slide", 1, ".code main.txt"); err == nil {
+			t.Errorf("expected error for %q", src)
+		}
+	}
+}
+
+func TestParseCodeTabWidth(t *testing.T) {
+	src := "func f() {\n\tif x {\t// x  \n\t\treturn\n\t}\n}\n"
+	ctx := &Context{ReadFile: func(string) ([]byte, error) { return []byte(src), nil }}
+
+	e, err := parseCode(ctx, "talk.slide", 1, ".code -tabwidth 2 main.txt /if/,/^\t}/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<pre><span num="2">if x {  // x</span>
+<span num="3">  return</span>
+<span num="4">}</span>
+</pre>`
+	if got := strings.TrimSpace(string(e.(Code).Text)); got != want {
+		t.Errorf("got Text\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := parseCode(ctx, "talk.slide", 1, ".code -tabwidth 0 main.txt"); err == nil {
+		t.Error("expected error for tab width 0")
+	}
+}
+
+func TestParseCodeEmpty(t *testing.T) {
+	tests := []struct {
+		src, cmd string
+	}{
+		{"", ".code main.txt"},
+		{"\n\n", ".code main.txt"},
+		{"a\n\n\nb\n", ".code main.txt 2,3"},
+	}
+
+	for _, tt := range tests {
+		ctx := &Context{ReadFile: func(string) ([]byte, error) { return []byte(tt.src), nil }}
+
+		e, err := parseCode(ctx, "talk.slide", 1, tt.cmd)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tt.src, err)
+			continue
+		}
+		if got := strings.TrimSpace(string(e.(Code).Text)); got != "<pre></pre>" {
+			t.Errorf("%q: got Text %q; want an empty block", tt.src, got)
+		}
+	}
+}
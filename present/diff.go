@@ -0,0 +1,384 @@
+package present
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	parsers[".diff"] = parseDiff
+}
+
+// Diff is the difference of two code files, or of a file at two revisions.
+type Diff struct {
+	Text     template.HTML
+	Lang     string // language of the code for highlighting, e.g. "go"
+	Old, New string // file names, with revisions if any, e.g. "main.go@v1"
+	Raw      []byte // unified diff
+}
+
+func (d Diff) TemplateName() string { return "diff" }
+
+var diffRE = regexp.MustCompile(`^\.diff\s+((?:(?:-split|-context\s+\d+|-(?:lang|tabwidth)\s+\S+)\s+)*)(\S+)\s+(\S+)(?:\s+(.*))?$`)
+
+var contextFlagRE = regexp.MustCompile(`-context\s+(\d+)`)
+
+// parseDiff parses a diff present directive. Its syntax:
+// .diff [-split] [-context <n>] [-lang <language>] [-tabwidth <n>] <old>[@rev] <new>[@rev] [address|region|symbol]
+// The address selects the lines of both files. With -split the files are
+// shown side by side, with -context unchanged lines further than n lines
+// from a change are collapsed.
+func parseDiff(ctx *Context, sourceFile string, sourceLine int, cmd string) (Elem, error) {
+	args := diffRE.FindStringSubmatch(strings.TrimSpace(cmd))
+	if args == nil {
+		return nil, fmt.Errorf("%s:%d: syntax error for .diff invocation", sourceFile, sourceLine)
+	}
+	flags, oldFile, newFile, addr := args[1], args[2], args[3], strings.TrimSpace(args[4])
+
+	tabWidth, err := ctx.tabWidthFlag(flags)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%d: %v", sourceFile, sourceLine, err)
+	}
+
+	read := func(file string) ([]codeLine, error) {
+		name, rev := splitRev(file)
+		lines, _, _, err := readCodeLines(ctx, sourceFile, name, rev, addr, false)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", sourceFile, sourceLine, err)
+		}
+		return formatLines(lines, "", tabWidth), nil
+	}
+
+	oldLines, err := read(oldFile)
+	if err != nil {
+		return nil, err
+	}
+	newLines, err := read(newFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Both files are dedented by the same amount, so that unchanged
+	// lines stay the same.
+	all := dedentLines(append(append([]codeLine{}, oldLines...), newLines...))
+	oldLines, newLines = all[:len(oldLines)], all[len(oldLines):]
+
+	name, _ := splitRev(newFile)
+	lang := strings.TrimPrefix(filepath.Ext(name), ".")
+	if m := langFlagRE.FindStringSubmatch(flags); m != nil {
+		lang = m[1]
+	}
+
+	ops := diffLines(oldLines, newLines)
+	if m := contextFlagRE.FindStringSubmatch(flags); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		ops = collapseDiff(ops, n)
+	}
+
+	oldLines = highlightCode(oldLines, lang)
+	newLines = highlightCode(newLines, lang)
+
+	data := &diffTemplateData{}
+	if strings.Contains(flags, "-split") {
+		data.Left, data.Right = splitDiffRows(ops, oldLines, newLines)
+	} else {
+		data.Lines = unifiedDiffRows(ops, oldLines, newLines)
+	}
+
+	var buf bytes.Buffer
+	if err := diffTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return Diff{
+		Text: template.HTML(buf.String()),
+		Lang: lang,
+		Old:  oldFile,
+		New:  newFile,
+		Raw:  rawDiff(ops, oldLines, newLines),
+	}, nil
+}
+
+// diffOp is a line of a diff.
+type diffOp struct {
+	Op       byte // '=', '-' or '+', or 's' for collapsed lines
+	Old, New int  // indexes of the old and new lines, -1 if none
+	Skip     int  // number of collapsed lines
+}
+
+// diffLines returns the line by line difference of a and b, based on
+// their longest common subsequence. The common prefix and suffix are
+// skipped, the rest is matched in linear space, so whole files can be
+// compared.
+func diffLines(a, b []codeLine) []diffOp {
+	// Compare lines by number instead of by text.
+	ids := make(map[string]int)
+	id := func(lines []codeLine) []int {
+		result := make([]int, len(lines))
+		for i, l := range lines {
+			n, ok := ids[l.L]
+			if !ok {
+				n = len(ids)
+				ids[l.L] = n
+			}
+			result[i] = n
+		}
+		return result
+	}
+	x, y := id(a), id(b)
+
+	start := 0
+	for start < len(x) && start < len(y) && x[start] == y[start] {
+		start++
+	}
+	end := 0
+	for end < len(x)-start && end < len(y)-start && x[len(x)-1-end] == y[len(y)-1-end] {
+		end++
+	}
+
+	// Pairs of matching lines, in order, ending with a sentinel after
+	// both files.
+	var matches [][2]int
+	for i := 0; i < start; i++ {
+		matches = append(matches, [2]int{i, i})
+	}
+	matches = lcsMatches(x[start:len(x)-end], y[start:len(y)-end], start, start, matches)
+	for i := end; i > 0; i-- {
+		matches = append(matches, [2]int{len(x) - i, len(y) - i})
+	}
+	matches = append(matches, [2]int{len(x), len(y)})
+
+	var ops []diffOp
+	i, j := 0, 0
+	for _, m := range matches {
+		for ; i < m[0]; i++ {
+			ops = append(ops, diffOp{Op: '-', Old: i, New: -1})
+		}
+		for ; j < m[1]; j++ {
+			ops = append(ops, diffOp{Op: '+', Old: -1, New: j})
+		}
+		if i < len(x) {
+			ops = append(ops, diffOp{Op: '=', Old: i, New: j})
+			i++
+			j++
+		}
+	}
+	return ops
+}
+
+// lcsMatches appends the pairs of indexes of a longest common subsequence
+// of x and y to matches, offset by xo and yo. It splits x in half and y
+// where the subsequences of both halves are longest (Hirschberg's
+// algorithm), which needs space linear in the length of y.
+func lcsMatches(x, y []int, xo, yo int, matches [][2]int) [][2]int {
+	if len(x) == 0 || len(y) == 0 {
+		return matches
+	}
+
+	if len(x) == 1 {
+		for j, v := range y {
+			if v == x[0] {
+				return append(matches, [2]int{xo, yo + j})
+			}
+		}
+		return matches
+	}
+
+	mid := len(x) / 2
+	head := lcsLengths(x[:mid], y)
+	tail := lcsLengthsReverse(x[mid:], y)
+
+	k := 0
+	for j := range head {
+		if head[j]+tail[j] > head[k]+tail[k] {
+			k = j
+		}
+	}
+
+	matches = lcsMatches(x[:mid], y[:k], xo, yo, matches)
+	return lcsMatches(x[mid:], y[k:], xo+mid, yo+k, matches)
+}
+
+// lcsLengths returns the lengths of the longest common subsequences of x
+// and y[:j], for every j.
+func lcsLengths(x, y []int) []int {
+	prev := make([]int, len(y)+1)
+	cur := make([]int, len(y)+1)
+	for _, v := range x {
+		for j, w := range y {
+			switch {
+			case v == w:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] >= cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// lcsLengthsReverse returns the lengths of the longest common
+// subsequences of x and y[j:], for every j.
+func lcsLengthsReverse(x, y []int) []int {
+	prev := make([]int, len(y)+1)
+	cur := make([]int, len(y)+1)
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			switch {
+			case x[i] == y[j]:
+				cur[j] = prev[j+1] + 1
+			case prev[j] >= cur[j+1]:
+				cur[j] = prev[j]
+			default:
+				cur[j] = cur[j+1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// collapseDiff replaces unchanged lines further than context lines from
+// a change with a single skip op, if more than one line would be skipped.
+func collapseDiff(ops []diffOp, context int) []diffOp {
+	var result []diffOp
+	for i := 0; i < len(ops); {
+		if ops[i].Op != '=' {
+			result = append(result, ops[i])
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(ops) && ops[j].Op == '=' {
+			j++
+		}
+
+		// Keep context lines after the previous change and before the next.
+		head, tail := context, context
+		if i == 0 {
+			head = 0
+		}
+		if j == len(ops) {
+			tail = 0
+		}
+
+		if skip := j - i - head - tail; skip > 1 {
+			result = append(result, ops[i:i+head]...)
+			result = append(result, diffOp{Op: 's', Old: -1, New: -1, Skip: skip})
+			result = append(result, ops[j-tail:j]...)
+		} else {
+			result = append(result, ops[i:j]...)
+		}
+		i = j
+	}
+	return result
+}
+
+// diffRow is a rendered line of a diff.
+type diffRow struct {
+	Class string // "eq", "add", "del", "skip", or "empty" for fillers
+	N     int    // line number in the source file
+	H     template.HTML
+}
+
+func unifiedDiffRows(ops []diffOp, oldLines, newLines []codeLine) []diffRow {
+	var rows []diffRow
+	for _, op := range ops {
+		switch op.Op {
+		case '=':
+			rows = append(rows, diffRow{"eq", newLines[op.New].N, newLines[op.New].H})
+		case '-':
+			rows = append(rows, diffRow{"del", oldLines[op.Old].N, oldLines[op.Old].H})
+		case '+':
+			rows = append(rows, diffRow{"add", newLines[op.New].N, newLines[op.New].H})
+		case 's':
+			rows = append(rows, skipRow(op.Skip))
+		}
+	}
+	return rows
+}
+
+// splitDiffRows returns the rows of the old and new side of a side by side
+// diff. Removed and added lines of a change are shown next to each other,
+// the shorter side is filled with empty rows.
+func splitDiffRows(ops []diffOp, oldLines, newLines []codeLine) (left, right []diffRow) {
+	var dels, adds []diffRow
+
+	flush := func() {
+		for len(dels) < len(adds) {
+			dels = append(dels, diffRow{Class: "empty"})
+		}
+		for len(adds) < len(dels) {
+			adds = append(adds, diffRow{Class: "empty"})
+		}
+		left = append(left, dels...)
+		right = append(right, adds...)
+		dels, adds = nil, nil
+	}
+
+	for _, op := range ops {
+		switch op.Op {
+		case '-':
+			dels = append(dels, diffRow{"del", oldLines[op.Old].N, oldLines[op.Old].H})
+		case '+':
+			adds = append(adds, diffRow{"add", newLines[op.New].N, newLines[op.New].H})
+		case '=':
+			flush()
+			left = append(left, diffRow{"eq", oldLines[op.Old].N, oldLines[op.Old].H})
+			right = append(right, diffRow{"eq", newLines[op.New].N, newLines[op.New].H})
+		case 's':
+			flush()
+			left = append(left, skipRow(op.Skip))
+			right = append(right, skipRow(op.Skip))
+		}
+	}
+	flush()
+
+	return left, right
+}
+
+func skipRow(n int) diffRow {
+	return diffRow{Class: "skip", H: template.HTML(fmt.Sprintf("⋯ %d unchanged lines", n))}
+}
+
+// rawDiff returns the unified diff of the given ops as text.
+func rawDiff(ops []diffOp, oldLines, newLines []codeLine) []byte {
+	b := new(bytes.Buffer)
+	for _, op := range ops {
+		switch op.Op {
+		case '=':
+			fmt.Fprintf(b, " %s\n", newLines[op.New].L)
+		case '-':
+			fmt.Fprintf(b, "-%s\n", oldLines[op.Old].L)
+		case '+':
+			fmt.Fprintf(b, "+%s\n", newLines[op.New].L)
+		case 's':
+			fmt.Fprintf(b, "@@ %d unchanged lines @@\n", op.Skip)
+		}
+	}
+	return b.Bytes()
+}
+
+type diffTemplateData struct {
+	Lines       []diffRow // unified
+	Left, Right []diffRow // side by side
+}
+
+var diffTemplate = template.Must(template.New("diff").Parse(diffTemplateHTML))
+
+const diffTemplateHTML = `
+{{define "rows"}}<pre class="diff">{{range .}}<span class="diff-{{.Class}}"{{with .N}} num="{{.}}"{{end}}>{{.H}}</span>
+{{end}}</pre>{{end}}
+
+{{if .Left}}<div class="diff-split">{{template "rows" .Left}}{{template "rows" .Right}}</div>{{/*
+*/}}{{else}}{{template "rows" .Lines}}{{end}}
+`
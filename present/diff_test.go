@@ -0,0 +1,183 @@
+package present
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestParseDiff(t *testing.T) {
+	files := map[string]string{
+		"old.txt":   "a\nb\nc\nd\ne\nf\n",
+		"new.txt":   "a\nb\nC\nd\ne\nf\ng\n",
+		"empty.txt": "",
+	}
+	ctx := &Context{
+		ReadFile: func(name string) ([]byte, error) { return []byte(files[name]), nil },
+	}
+
+	tests := []struct {
+		cmd  string
+		want string
+	}{
+		{
+			cmd:  ".diff old.txt new.txt",
+			want: " a\n b\n-c\n+C\n d\n e\n f\n+g\n",
+		},
+		{
+			cmd:  ".diff -context 1 old.txt new.txt",
+			want: " a\n b\n-c\n+C\n d\n e\n f\n+g\n",
+		},
+		{
+			cmd:  ".diff -context 0 old.txt new.txt",
+			want: "@@ 2 unchanged lines @@\n-c\n+C\n@@ 3 unchanged lines @@\n+g\n",
+		},
+		{
+			cmd:  ".diff old.txt new.txt /d/,",
+			want: " d\n e\n f\n+g\n",
+		},
+		{
+			// a new file
+			cmd:  ".diff empty.txt old.txt",
+			want: "+a\n+b\n+c\n+d\n+e\n+f\n",
+		},
+		{
+			// a deleted file
+			cmd:  ".diff -split old.txt empty.txt",
+			want: "-a\n-b\n-c\n-d\n-e\n-f\n",
+		},
+	}
+
+	for _, tt := range tests {
+		e, err := parseDiff(ctx, "talk.slide", 1, tt.cmd)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.cmd, err)
+			continue
+		}
+		if got := string(e.(Diff).Raw); got != tt.want {
+			t.Errorf("%s: got diff\n%s\nwant\n%s", tt.cmd, got, tt.want)
+		}
+	}
+
+	e, err := parseDiff(ctx, "talk.slide", 1, ".diff -split old.txt new.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(e.(Diff).Text)
+	if !strings.Contains(text, `<div class="diff-split">`) || !strings.Contains(text, `<span class="diff-empty"></span>`) {
+		t.Errorf("unexpected split diff %s", text)
+	}
+
+	if _, err := parseDiff(ctx, "talk.slide", 1, ".diff old.txt"); err == nil {
+		t.Errorf("expected syntax error")
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	lines := func(s string) []codeLine {
+		var result []codeLine
+		for i, r := range s {
+			result = append(result, codeLine{L: string(r), N: i + 1})
+		}
+		return result
+	}
+
+	// lcsLength is the textbook quadratic longest common subsequence.
+	lcsLength := func(a, b string) int {
+		l := make([][]int, len(a)+1)
+		for i := range l {
+			l[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				switch {
+				case a[i] == b[j]:
+					l[i][j] = l[i+1][j+1] + 1
+				case l[i+1][j] > l[i][j+1]:
+					l[i][j] = l[i+1][j]
+				default:
+					l[i][j] = l[i][j+1]
+				}
+			}
+		}
+		return l[0][0]
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	random := func() string {
+		b := make([]byte, rnd.Intn(30))
+		for i := range b {
+			b[i] = "abcd"[rnd.Intn(4)]
+		}
+		return string(b)
+	}
+
+	tests := [][2]string{
+		{"", ""},
+		{"abc", ""},
+		{"", "abc"},
+		{"abc", "abc"},
+		{"abcabba", "cbabac"},
+		{"xaby", "xcy"},
+	}
+	for i := 0; i < 200; i++ {
+		tests = append(tests, [2]string{random(), random()})
+	}
+
+	for _, tt := range tests {
+		a, b := tt[0], tt[1]
+		ops := diffLines(lines(a), lines(b))
+
+		// Applying the ops to a gives b, keeping a longest common
+		// subsequence.
+		var gotA, gotB []byte
+		same := 0
+		for _, op := range ops {
+			switch op.Op {
+			case '=':
+				if a[op.Old] != b[op.New] {
+					t.Fatalf("%q %q: op %+v matches different lines", a, b, op)
+				}
+				gotA, gotB = append(gotA, a[op.Old]), append(gotB, b[op.New])
+				same++
+			case '-':
+				gotA = append(gotA, a[op.Old])
+			case '+':
+				gotB = append(gotB, b[op.New])
+			}
+		}
+		if string(gotA) != a || string(gotB) != b {
+			t.Errorf("%q %q: ops give %q %q", a, b, gotA, gotB)
+		}
+		if want := lcsLength(a, b); same != want {
+			t.Errorf("%q %q: %d unchanged lines; want %d", a, b, same, want)
+		}
+	}
+}
+
+func TestDiffLinesLarge(t *testing.T) {
+	var a, b []codeLine
+	for i := 0; i < 5000; i++ {
+		a = append(a, codeLine{L: fmt.Sprintf("line %d", i)})
+		b = append(b, codeLine{L: fmt.Sprintf("line %d", i)})
+	}
+	b[2500].L = "changed"
+	b = append(b[:100], b[101:]...)
+
+	// The quadratic table of this diff would take 200MB.
+	ops := diffLines(a, b)
+
+	var changes []string
+	for _, op := range ops {
+		switch op.Op {
+		case '-':
+			changes = append(changes, "-"+a[op.Old].L)
+		case '+':
+			changes = append(changes, "+"+b[op.New].L)
+		}
+	}
+	if got, want := strings.Join(changes, ","), "-line 100,-line 2500,+changed"; got != want {
+		t.Errorf("got changes %s; want %s", got, want)
+	}
+}
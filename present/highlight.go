@@ -0,0 +1,327 @@
+package present
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// A simple regexp based syntax highlighter. It produces spans with the
+// token classes used by highlight.js, so the hljs.css themes still apply.
+
+// codeToken is a piece of code with a highlight class, an empty class
+// means plain text.
+type codeToken struct {
+	class string
+	text  string
+}
+
+// identClass is the placeholder class of identifiers, they are looked up
+// in the keywords of the language.
+const identClass = "ident"
+
+type rule struct {
+	re    *regexp.Regexp
+	class string
+
+	// lineStart rules only match at the beginning of a line.
+	lineStart bool
+}
+
+type language struct {
+	rules    []rule
+	keywords map[string]string // identifier -> class
+
+	// ignoreCase languages look up lowercased identifiers.
+	ignoreCase bool
+}
+
+// rules returns rules from pairs of class and pattern. Patterns are
+// anchored at the current position, a leading ^ anchors them at the
+// beginning of a line.
+func rules(pairs ...string) []rule {
+	var r []rule
+	for i := 0; i < len(pairs); i += 2 {
+		pattern := pairs[i+1]
+		lineStart := strings.HasPrefix(pattern, "^")
+		r = append(r, rule{
+			class:     pairs[i],
+			re:        regexp.MustCompile(`\A(?:` + strings.TrimPrefix(pattern, "^") + `)`),
+			lineStart: lineStart,
+		})
+	}
+	return r
+}
+
+// keywords returns a keyword map from pairs of class and space separated
+// words.
+func keywords(pairs ...string) map[string]string {
+	m := make(map[string]string)
+	for i := 0; i < len(pairs); i += 2 {
+		for _, w := range strings.Fields(pairs[i+1]) {
+			m[w] = pairs[i]
+		}
+	}
+	return m
+}
+
+const (
+	identPattern   = `[\pL_$][\pL\pN_$]*`
+	numberPattern  = `0[xX][0-9a-fA-F_]+|\d[\d_]*(?:\.\d+)?(?:[eE][+-]?\d+)?`
+	dqStringPat    = `"(?:[^"\\\n]|\\.)*"`
+	sqStringPat    = `'(?:[^'\\\n]|\\.)*'`
+	lineCommentPat = `//[^\n]*`
+	blockCommentPt = `/\*[\s\S]*?(?:\*/|\z)`
+)
+
+var languages = map[string]*language{}
+
+func init() {
+	golang := &language{
+		rules: rules(
+			"hljs-comment", lineCommentPat+`|`+blockCommentPt,
+			"hljs-string", dqStringPat+"|`[^`]*`?",
+			"hljs-string", sqStringPat,
+			"hljs-number", numberPattern,
+			identClass, identPattern,
+		),
+		keywords: keywords(
+			"hljs-keyword", "break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var",
+			"hljs-literal", "true false nil iota",
+			"hljs-built_in", "append cap close complex copy delete imag len make new panic print println real recover",
+			"hljs-type", "bool byte complex64 complex128 error float32 float64 int int8 int16 int32 int64 rune string uint uint8 uint16 uint32 uint64 uintptr",
+		),
+	}
+
+	js := &language{
+		rules: rules(
+			"hljs-comment", lineCommentPat+`|`+blockCommentPt,
+			"hljs-string", dqStringPat+`|`+sqStringPat+"|`(?:[^`\\\\]|\\\\.)*`?",
+			"hljs-number", numberPattern,
+			identClass, identPattern,
+		),
+		keywords: keywords(
+			"hljs-keyword", "as async await break case catch class const continue debugger default delete do else enum export extends finally for from function get if implements import in instanceof interface let new of package private protected public readonly return set static super switch this throw try type typeof var void while with yield",
+			"hljs-literal", "true false null undefined NaN Infinity",
+			"hljs-built_in", "Array Boolean Date Error JSON Map Math Number Object Promise RegExp Set String Symbol console document window require module exports",
+			"hljs-type", "any boolean number string never unknown",
+		),
+	}
+
+	python := &language{
+		rules: rules(
+			"hljs-comment", `#[^\n]*`,
+			"hljs-string", `(?i:[rbuf]{0,2})(?:"""[\s\S]*?(?:"""|\z)|'''[\s\S]*?(?:'''|\z))`,
+			"hljs-string", `(?i:[rbuf]{0,2})(?:`+dqStringPat+`|`+sqStringPat+`)`,
+			"hljs-meta", `@`+identPattern,
+			"hljs-number", numberPattern,
+			identClass, identPattern,
+		),
+		keywords: keywords(
+			"hljs-keyword", "and as assert async await break class continue def del elif else except finally for from global if import in is lambda nonlocal not or pass raise return try while with yield",
+			"hljs-literal", "True False None",
+			"hljs-built_in", "abs all any bool dict enumerate filter float input int isinstance len list map max min object open print range repr set sorted str sum super tuple type zip self",
+		),
+	}
+
+	shell := &language{
+		rules: rules(
+			"hljs-comment", `#[^\n]*`,
+			"hljs-string", `"(?:[^"\\]|\\[\s\S])*"|'[^']*'`,
+			"hljs-variable", `\$(?:\{[^}\n]*\}|[\w@#?$!*-])`+`[\w]*`,
+			"hljs-number", `\b\d+\b`,
+			identClass, `[\pL_][\pL\pN_.-]*`,
+		),
+		keywords: keywords(
+			"hljs-keyword", "if then else elif fi for while until do done case esac in function select return",
+			"hljs-built_in", "alias bg cd echo eval exec exit export fg jobs kill local printf pwd read readonly set shift source test trap type ulimit umask unset wait",
+		),
+	}
+
+	sql := &language{
+		rules: rules(
+			"hljs-comment", `--[^\n]*|`+blockCommentPt,
+			"hljs-string", `'(?:[^']|'')*'?`,
+			"hljs-string", `"[^"]*"|`+"`[^`]*`",
+			"hljs-number", numberPattern,
+			identClass, `[\pL_][\pL\pN_]*`,
+		),
+		ignoreCase: true,
+		keywords: keywords(
+			"hljs-keyword", "add all alter and as asc begin between by case check column commit constraint create database default delete desc distinct drop else end exists foreign from full group having if in index inner insert into is join key left like limit not null offset on or order outer primary references returning right rollback select set table then transaction union unique update using values view when where with",
+			"hljs-type", "bigint bool boolean char date decimal float int integer json jsonb numeric real serial smallint text time timestamp uuid varchar",
+			"hljs-literal", "true false",
+			"hljs-built_in", "avg coalesce count max min now sum",
+		),
+	}
+
+	yaml := &language{
+		rules: rules(
+			"hljs-comment", `#[^\n]*`,
+			"hljs-meta", `---|\.\.\.`,
+			"hljs-attr", `[\w][\w .-]*:(?:\s|\z)`,
+			"hljs-string", dqStringPat+`|`+sqStringPat,
+			"hljs-bullet", `- `,
+			"hljs-number", `-?`+numberPattern,
+			identClass, identPattern,
+		),
+		keywords: keywords(
+			"hljs-literal", "true false null yes no on off",
+		),
+	}
+
+	json := &language{
+		rules: rules(
+			"hljs-attr", dqStringPat+`(?:\s*:)`,
+			"hljs-string", dqStringPat,
+			"hljs-number", `-?`+numberPattern,
+			identClass, identPattern,
+		),
+		keywords: keywords(
+			"hljs-literal", "true false null",
+		),
+	}
+
+	diff := &language{
+		rules: rules(
+			"hljs-meta", `^(?:@@|diff |index |\+\+\+|---)[^\n]*`,
+			"hljs-addition", `^\+[^\n]*`,
+			"hljs-deletion", `^-[^\n]*`,
+		),
+	}
+
+	for _, l := range []struct {
+		lang  *language
+		names string
+	}{
+		{golang, "go golang"},
+		{js, "js javascript jsx mjs ts typescript tsx"},
+		{python, "py python"},
+		{shell, "sh bash shell zsh"},
+		{sql, "sql"},
+		{yaml, "yaml yml"},
+		{json, "json"},
+		{diff, "diff patch"},
+	} {
+		for _, name := range strings.Fields(l.names) {
+			languages[name] = l.lang
+		}
+	}
+}
+
+// tokenize splits code into tokens of lang. Unknown languages produce a
+// single plain token.
+func tokenize(code, lang string) []codeToken {
+	l := languages[strings.ToLower(lang)]
+	if l == nil {
+		return []codeToken{{text: code}}
+	}
+
+	var tokens []codeToken
+	var plain strings.Builder
+
+	flush := func() {
+		if plain.Len() > 0 {
+			tokens = append(tokens, codeToken{text: plain.String()})
+			plain.Reset()
+		}
+	}
+
+Code:
+	for i := 0; i < len(code); {
+		rest := code[i:]
+
+		// Identifiers can't start in the middle of a word.
+		inWord := i > 0 && isWordByte(code[i-1])
+
+		for _, r := range l.rules {
+			if r.lineStart && i > 0 && code[i-1] != '\n' {
+				continue
+			}
+			if (r.class == identClass || r.class == "hljs-number") && inWord {
+				continue
+			}
+
+			m := r.re.FindString(rest)
+			if m == "" {
+				continue
+			}
+
+			// Trailing blanks of a token, e.g. after "key:" in yaml, are plain.
+			if t := strings.TrimRight(m, " \t"); t != "" {
+				m = t
+			}
+
+			class := r.class
+			if class == identClass {
+				word := m
+				if l.ignoreCase {
+					word = strings.ToLower(word)
+				}
+				class = l.keywords[word]
+			}
+
+			if class == "" {
+				plain.WriteString(m)
+			} else {
+				flush()
+				tokens = append(tokens, codeToken{class: class, text: m})
+			}
+
+			i += len(m)
+			continue Code
+		}
+
+		plain.WriteByte(code[i])
+		i++
+	}
+
+	flush()
+	return tokens
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b == '$' || b >= 0x80 ||
+		'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9'
+}
+
+// highlightLines returns the highlighted HTML of each line of code.
+// Tokens spanning multiple lines are split, so every line is valid HTML
+// on its own.
+func highlightLines(code, lang string) []template.HTML {
+	var lines []template.HTML
+	var b strings.Builder
+
+	for _, t := range tokenize(code, lang) {
+		parts := strings.Split(t.text, "\n")
+		for i, part := range parts {
+			if i > 0 {
+				lines = append(lines, template.HTML(b.String()))
+				b.Reset()
+			}
+			if part == "" {
+				continue
+			}
+			if t.class == "" {
+				b.WriteString(template.HTMLEscapeString(part))
+				continue
+			}
+			b.WriteString(`<span class="` + t.class + `">`)
+			b.WriteString(template.HTMLEscapeString(part))
+			b.WriteString(`</span>`)
+		}
+	}
+
+	return append(lines, template.HTML(b.String()))
+}
+
+// Highlight returns code as HTML, with tokens wrapped in spans using the
+// highlight.js classes. Code of unknown languages is only escaped.
+func Highlight(code, lang string) template.HTML {
+	lines := highlightLines(code, lang)
+	s := make([]string, len(lines))
+	for i, l := range lines {
+		s[i] = string(l)
+	}
+	return template.HTML(strings.Join(s, "\n"))
+}
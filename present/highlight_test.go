@@ -0,0 +1,56 @@
+package present
+
+import (
+	"html/template"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		lang string
+		code string
+		want template.HTML
+	}{
+		{
+			lang: "go",
+			code: "return nil // done",
+			want: `<span class="hljs-keyword">return</span> <span class="hljs-literal">nil</span> <span class="hljs-comment">// done</span>`,
+		},
+		{
+			lang: "go",
+			code: "x := `a\nb`",
+			want: "x := <span class=\"hljs-string\">`a</span>\n<span class=\"hljs-string\">b`</span>",
+		},
+		{
+			lang: "go",
+			code: "forward2",
+			want: "forward2",
+		},
+		{
+			lang: "sql",
+			code: "select 1",
+			want: `<span class="hljs-keyword">select</span> <span class="hljs-number">1</span>`,
+		},
+		{
+			lang: "yaml",
+			code: "on: true",
+			want: `<span class="hljs-attr">on:</span> <span class="hljs-literal">true</span>`,
+		},
+		{
+			lang: "diff",
+			code: "-a\n+b",
+			want: "<span class=\"hljs-deletion\">-a</span>\n<span class=\"hljs-addition\">+b</span>",
+		},
+		{
+			lang: "unknown",
+			code: "<b>",
+			want: "&lt;b&gt;",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := Highlight(tt.code, tt.lang); got != tt.want {
+			t.Errorf("Highlight(%q, %q) = %q; want %q", tt.code, tt.lang, got, tt.want)
+		}
+	}
+}
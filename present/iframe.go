@@ -19,7 +19,7 @@ func (i Iframe) TemplateName() string { return "iframe" }
 
 func parseIframe(ctx *Context, fileName string, lineno int, text string) (Elem, error) {
 	args := strings.Fields(text)
-	i := Iframe{URL: args[1]}
+	i := Iframe{URL: ctx.resolveURL(fileName, args[1])}
 	a, err := parseArgs(fileName, lineno, args[2:])
 	if err != nil {
 		return nil, err
@@ -6,11 +6,13 @@ package present
 
 import (
 	"fmt"
+	"html/template"
 	"strings"
 )
 
 type Image struct {
 	URL    string
+	SrcSet template.Srcset // resized variants, if any
 	Width  int
 	Height int
 }
@@ -19,7 +21,8 @@ func (i Image) TemplateName() string { return "image" }
 
 func parseImage(ctx *Context, fileName string, lineno int, text string) (Elem, error) {
 	args := strings.Fields(text)
-	img := Image{URL: args[1]}
+	img := Image{URL: ctx.resolveURL(fileName, args[1])}
+	img.SrcSet = srcSet(ctx.imageVariants(img.URL))
 	a, err := parseArgs(fileName, lineno, args[2:])
 	if err != nil {
 		return nil, err
@@ -44,3 +47,12 @@ func parseImage(ctx *Context, fileName string, lineno int, text string) (Elem, e
 	}
 	return img, nil
 }
+
+// srcSet formats variants as the value of an img srcset attribute.
+func srcSet(variants []ImageVariant) template.Srcset {
+	var s []string
+	for _, v := range variants {
+		s = append(s, fmt.Sprintf("%s %dw", v.URL, v.Width))
+	}
+	return template.Srcset(strings.Join(s, ", "))
+}
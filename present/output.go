@@ -0,0 +1,58 @@
+package present
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	parsers[".output"] = parseOutput
+}
+
+// Output is the output of a command, captured when the slide is rendered.
+type Output struct {
+	Command string
+	Text    string // stdout of the command
+}
+
+func (o Output) TemplateName() string { return "output" }
+
+var outputRE = regexp.MustCompile(`^\.output\s+(?:-inputs\s+(\S+)\s+)?(.+)$`)
+
+// ansiRE matches ANSI escape sequences, e.g. colors of test output.
+var ansiRE = regexp.MustCompile("\x1b\\[[0-9;?]*[a-zA-Z]")
+
+// parseOutput parses an output present directive. Its syntax:
+// .output [-inputs <glob>,<glob>...] <command>
+// The command runs in the directory of the slide. Inputs are the files
+// the output depends on, relative to the slide, all files in its directory
+// by default.
+func parseOutput(ctx *Context, sourceFile string, sourceLine int, cmd string) (Elem, error) {
+	args := outputRE.FindStringSubmatch(strings.TrimSpace(cmd))
+	if args == nil {
+		return nil, fmt.Errorf("%s:%d: syntax error for .output invocation", sourceFile, sourceLine)
+	}
+
+	var inputs []string
+	if args[1] != "" {
+		inputs = strings.Split(args[1], ",")
+	}
+	command := strings.TrimSpace(args[2])
+
+	if ctx.RunCommand == nil {
+		return nil, fmt.Errorf("%s:%d: commands are not supported", sourceFile, sourceLine)
+	}
+
+	out, err := ctx.RunCommand(filepath.Dir(sourceFile), command, inputs)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%d: %v", sourceFile, sourceLine, err)
+	}
+
+	text := ansiRE.ReplaceAllString(string(out), "")
+	text = expandTabs(text, ctx.tabWidth)
+	text = strings.TrimRight(text, " \t\r\n")
+
+	return Output{Command: command, Text: text}, nil
+}
@@ -0,0 +1,43 @@
+package present
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseOutput(t *testing.T) {
+	var gotDir, gotCommand string
+	var gotInputs []string
+
+	ctx := &Context{
+		RunCommand: func(dir, command string, inputs []string) ([]byte, error) {
+			gotDir, gotCommand, gotInputs = dir, command, inputs
+			return []byte("\x1b[32mok\x1b[0m\tpkg\n\n"), nil
+		},
+	}
+
+	e, err := parseOutput(ctx, "talks/go.slide", 1, ".output -inputs a.go,b/*.go go test ./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Output{Command: "go test ./...", Text: "ok  pkg"}
+	if e != want {
+		t.Errorf("got %#v; want %#v", e, want)
+	}
+	if gotDir != "talks" || gotCommand != "go test ./..." || !reflect.DeepEqual(gotInputs, []string{"a.go", "b/*.go"}) {
+		t.Errorf("got dir %q, command %q, inputs %q", gotDir, gotCommand, gotInputs)
+	}
+
+	// tabs stop at the tab width of the document
+	ctx.tabWidth = 8
+	if e, _ := parseOutput(ctx, "talks/go.slide", 1, ".output go test"); e.(Output).Text != "ok      pkg" {
+		t.Errorf("got %q with tab width 8", e.(Output).Text)
+	}
+
+	ctx.RunCommand = nil
+	if _, err := parseOutput(ctx, "talks/go.slide", 1, ".output ls"); err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("expected unsupported error, got %v", err)
+	}
+}
@@ -23,6 +23,7 @@ var (
 
 func init() {
 	parsers[".code"] = parseCode
+	parsers[".play"] = parseCode
 	parsers[".link"] = parseLink
 	parsers[".iframe"] = parseIframe
 	parsers[".html"] = parseHTML
@@ -49,10 +50,40 @@ type Doc struct {
 	Time       time.Time
 	TitleNotes []string
 	Cover      string
+	Tags       []string
+	Event      string
+	Author     string
+	Theme      string    // theme color, e.g. #3f51b5
+	TabWidth   int       // tab width of code, 0 for the default
+	Status     string    // one of the Status constants
+	Publish    time.Time // publish time of a scheduled document
 	Misc       []string
 	Sections   []Section
 }
 
+// Publishing status of a document, set by the `.status` header.
+const (
+	StatusPublished = ""
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled"
+	StatusArchived  = "archived"
+)
+
+// StatusAt returns the publishing status of the document at time now.
+// A scheduled document, or one dated after now, is scheduled until its time
+// comes, and published afterwards.
+func (d *Doc) StatusAt(now time.Time) string {
+	switch {
+	case d.Status == StatusScheduled && d.Publish.After(now):
+		return StatusScheduled
+	case d.Status == StatusScheduled:
+		return StatusPublished
+	case d.Status == StatusPublished && d.Time.After(now):
+		return StatusScheduled
+	}
+	return d.Status
+}
+
 // Section represents a section of a document (such as a presentation slide)
 // comprising a title and a list of elements.
 type Section struct {
@@ -141,7 +172,8 @@ func execTemplate(t *template.Template, name string, data interface{}) (template
 type Text struct {
 	Lines []string
 	Pre   bool
-	Lang  string // lang code for pre text
+	Lang  string        // lang code for pre text
+	HTML  template.HTML // syntax highlighted pre text
 }
 
 func (t Text) TemplateName() string { return "text" }
@@ -210,6 +242,73 @@ func (l *Lines) nextNonEmpty() (text string, ok bool) {
 type Context struct {
 	// ReadFile reads the file named by filename and returns the contents.
 	ReadFile func(filename string) ([]byte, error)
+
+	// ReadFileAt reads the file named by filename as of the revision rev,
+	// e.g. a commit, tag or branch of the repository containing the file.
+	// If nil, revisions are not supported.
+	ReadFileAt func(filename, rev string) ([]byte, error)
+
+	// RunCommand runs the shell command in dir and returns its stdout.
+	// Inputs are globs, relative to dir, of the files the output depends on,
+	// all files in dir if empty.
+	// If nil, commands are not supported.
+	RunCommand func(dir, command string, inputs []string) ([]byte, error)
+
+	// ResolveURL normalizes the asset reference ref found in the document
+	// named by filename, e.g. the url of an image relative to the document.
+	// If nil, references are used as is.
+	ResolveURL func(filename, ref string) string
+
+	// ImageVariants returns resized variants of the image at url, which is
+	// already resolved by ResolveURL, sorted by width. The original image
+	// is the last variant.
+	// If nil, images are used at their original size.
+	ImageVariants func(url string) []ImageVariant
+
+	// tabWidth is the tab width of the document being parsed, only set
+	// on the copy made by forDoc.
+	tabWidth int
+}
+
+// forDoc returns a copy of ctx for parsing the sections of doc.
+func (ctx *Context) forDoc(doc *Doc) *Context {
+	c := *ctx
+	c.tabWidth = doc.TabWidth
+	return &c
+}
+
+// ImageVariant is a resized version of an image, or the image itself.
+type ImageVariant struct {
+	URL   string
+	Width int
+}
+
+// imageVariants returns the variants of the image at url, if any.
+func (ctx *Context) imageVariants(url string) []ImageVariant {
+	if ctx.ImageVariants == nil {
+		return nil
+	}
+	return ctx.ImageVariants(url)
+}
+
+// readFileAt reads filename with ctx.ReadFileAt, or with ctx.ReadFile
+// if rev is empty.
+func (ctx *Context) readFileAt(filename, rev string) ([]byte, error) {
+	if rev == "" {
+		return ctx.ReadFile(filename)
+	}
+	if ctx.ReadFileAt == nil {
+		return nil, fmt.Errorf("%s@%s: revisions are not supported", filename, rev)
+	}
+	return ctx.ReadFileAt(filename, rev)
+}
+
+// resolveURL resolves ref with ctx.ResolveURL, if any.
+func (ctx *Context) resolveURL(filename, ref string) string {
+	if ctx.ResolveURL == nil || ref == "" {
+		return ref
+	}
+	return ctx.ResolveURL(filename, ref)
 }
 
 // ParseMode represents flags for the Parse function.
@@ -237,6 +336,12 @@ func (ctx *Context) Parse(r io.Reader, name string, mode ParseMode) (*Doc, error
 		return nil, err
 	}
 
+	doc.Cover = ctx.resolveURL(name, doc.Cover)
+
+	// The sections are parsed with a copy of ctx holding the state of
+	// the document, the Context of the caller is not changed.
+	ctx = ctx.forDoc(doc)
+
 	if mode&TitlesOnly != 0 {
 		return doc, nil
 	}
@@ -329,10 +434,27 @@ func parseSections(ctx *Context, name string, lines *Lines, number []int) ([]Sec
 					text, ok = lines.next()
 				}
 				lines.back()
-				pre := strings.Join(s, "\n")
-				pre = strings.Replace(pre, "\t", "    ", -1) // browsers treat tabs badly
+
+				// Flags follow the language, e.g. "#lang go -tabwidth 2".
+				var flags string
+				if i := strings.IndexFunc(lang, unicode.IsSpace); i >= 0 {
+					lang, flags = lang[:i], lang[i:]
+				}
+
+				tabWidth, err := ctx.tabWidthFlag(flags)
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: %v", name, lines.line, err)
+				}
+
+				pre := normalizeText(strings.Join(s, "\n"), tabWidth)
 				pre = strings.TrimRightFunc(pre, unicode.IsSpace)
-				e = Text{Lines: []string{pre}, Pre: true, Lang: lang}
+				// "#lang console" is a terminal session, "#lang console -steps"
+				// reveals one command at a time
+				if lang == "console" {
+					e = parseTermText(pre, strings.Contains(flags, "-steps"))
+				} else {
+					e = Text{Lines: []string{pre}, Pre: true, Lang: lang, HTML: Highlight(pre, lang)}
+				}
 
 			// list
 			case strings.HasPrefix(text, "- "):
@@ -363,7 +485,15 @@ func parseSections(ctx *Context, name string, lines *Lines, number []int) ([]Sec
 				args := strings.Fields(text)
 				if args[0] == ".background" {
 					section.Classes = append(section.Classes, "background")
-					section.Styles = append(section.Styles, "background-image: url('"+args[1]+"')")
+					url := ctx.resolveURL(name, args[1])
+					// Use the largest resized variant, backgrounds cover the whole slide.
+					bg := url
+					for _, v := range ctx.imageVariants(url) {
+						if v.URL != url {
+							bg = v.URL
+						}
+					}
+					section.Styles = append(section.Styles, "background-image: url('"+bg+"')")
 					break
 				}
 				parser := parsers[args[0]]
@@ -374,6 +504,10 @@ func parseSections(ctx *Context, name string, lines *Lines, number []int) ([]Sec
 				if err != nil {
 					return nil, err
 				}
+				if c, ok := t.(Code); ok {
+					c.Callouts = parseCallouts(lines)
+					t = c
+				}
 				e = t
 
 			default:
@@ -411,6 +545,9 @@ func parseSections(ctx *Context, name string, lines *Lines, number []int) ([]Sec
 	return sections, nil
 }
 
+// themeRE matches hex colors like #fff and #3f51b5.
+var themeRE = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}){1,2}$`)
+
 func parseHeader(doc *Doc, lines *Lines) error {
 	// first non-empty line starts header.
 	ok := false
@@ -441,6 +578,49 @@ func parseHeader(doc *Doc, lines *Lines) error {
 			continue
 		}
 
+		if strings.HasPrefix(text, ".tags ") {
+			for _, tag := range strings.Split(text[len(".tags "):], ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					doc.Tags = append(doc.Tags, tag)
+				}
+			}
+			continue
+		}
+
+		if strings.HasPrefix(text, ".event ") {
+			doc.Event = strings.TrimSpace(text[len(".event "):])
+			continue
+		}
+
+		if strings.HasPrefix(text, ".author ") {
+			doc.Author = strings.TrimSpace(text[len(".author "):])
+			continue
+		}
+
+		if strings.HasPrefix(text, ".theme ") {
+			doc.Theme = strings.TrimSpace(text[len(".theme "):])
+			if !themeRE.MatchString(doc.Theme) {
+				return fmt.Errorf("invalid theme color: %q", text)
+			}
+			continue
+		}
+
+		if strings.HasPrefix(text, ".tabwidth ") {
+			n, err := parseTabWidth(strings.TrimSpace(text[len(".tabwidth "):]))
+			if err != nil {
+				return err
+			}
+			doc.TabWidth = n
+			continue
+		}
+
+		if text == ".status" || strings.HasPrefix(text, ".status ") {
+			if err := parseStatus(doc, text); err != nil {
+				return err
+			}
+			continue
+		}
+
 		if t, ok := parseTime(text); ok {
 			doc.Time = t
 		} else if doc.Subtitle == "" {
@@ -453,6 +633,33 @@ func parseHeader(doc *Doc, lines *Lines) error {
 	return nil
 }
 
+// parseStatus parses the status header. Its syntax:
+// .status draft|archived|scheduled <time>
+func parseStatus(doc *Doc, text string) error {
+	args := strings.Fields(text)
+	if len(args) < 2 {
+		return fmt.Errorf(".status needs a value: %q", text)
+	}
+
+	switch args[1] {
+	case StatusDraft, StatusArchived:
+		if len(args) != 2 {
+			return fmt.Errorf("unexpected status arguments: %q", text)
+		}
+	case StatusScheduled:
+		t, ok := parseTime(strings.Join(args[2:], " "))
+		if !ok {
+			return fmt.Errorf("invalid publish time: %q", text)
+		}
+		doc.Publish = t
+	default:
+		return fmt.Errorf("unknown status: %q", text)
+	}
+
+	doc.Status = args[1]
+	return nil
+}
+
 func parseMisc(lines *Lines) []string {
 	var result []string
 
@@ -0,0 +1,42 @@
+package present
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		header string
+		status string
+		err    string
+	}{
+		{".status draft", StatusDraft, ""},
+		{".status archived", StatusArchived, ""},
+		{".status scheduled 2 Jan 2030", StatusScheduled, ""},
+		{".status", "", "needs a value"},
+		{".status ", "", "needs a value"},
+		{".status   ", "", "needs a value"},
+		{".status hidden", "", "unknown status"},
+		{".status draft now", "", "unexpected status arguments"},
+		{".status scheduled", "", "invalid publish time"},
+		{".status scheduled soon", "", "invalid publish time"},
+	}
+
+	for _, tt := range tests {
+		doc, err := Parse(strings.NewReader("Title\n"+tt.header+"\n"), "talk.slide", TitlesOnly)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%q: expected error %q; got %v", tt.header, tt.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tt.header, err)
+			continue
+		}
+		if doc.Status != tt.status {
+			t.Errorf("%q: got status %q; want %q", tt.header, doc.Status, tt.status)
+		}
+	}
+}
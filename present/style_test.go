@@ -53,7 +53,7 @@ func TestFont(t *testing.T) {
 		{"\tx", "\tx"},
 		{"_a_", "<i>a</i>"},
 		{"*a*", "<b>a</b>"},
-		{"`a`", "<code>a</code>"},
+		{"`a`", `<code class="inline">a</code>`},
 		{"_a_b_", "<i>a b</i>"},
 		{"_a__b_", "<i>a_b</i>"},
 		{"_a___b_", "<i>a_ b</i>"},
@@ -75,9 +75,9 @@ func TestFont(t *testing.T) {
 		{"Markup—_especially_italic_text_—can easily be overused.",
 			`Markup—<i>especially italic text</i>—can easily be overused.`},
 		{"`go`get`'s codebase", // ascii U+0027 ' before s
-			`<code>go get</code>'s codebase`},
+			`<code class="inline">go get</code>'s codebase`},
 		{"`go`get`’s codebase", // unicode right single quote U+2019 ’ before s
-			`<code>go get</code>’s codebase`},
+			`<code class="inline">go get</code>’s codebase`},
 		{"a_variable_name",
 			`a_variable_name`},
 	}
@@ -99,7 +99,7 @@ func TestStyle(t *testing.T) {
 		{"\tx", "\tx"},
 		{"_a_", "<i>a</i>"},
 		{"*a*", "<b>a</b>"},
-		{"`a`", "<code>a</code>"},
+		{"`a`", `<code class="inline">a</code>`},
 		{"_a_b_", "<i>a b</i>"},
 		{"_a__b_", "<i>a_b</i>"},
 		{"_a___b_", "<i>a_ b</i>"},
@@ -0,0 +1,126 @@
+package present
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+func init() {
+	parsers[".term"] = parseTerm
+}
+
+// Term is a terminal session, with commands separated from their output.
+type Term struct {
+	Commands []TermCommand
+	Steps    bool // reveal one command at a time
+}
+
+func (t Term) TemplateName() string { return "term" }
+
+// StepCount returns the number of steps to reveal all commands, the first
+// command is shown without a step.
+func (t Term) StepCount() int {
+	if len(t.Commands) == 0 {
+		return 0
+	}
+	return len(t.Commands) - 1
+}
+
+// TermCommand is a command of a terminal session and its output. Output
+// before the first command has no prompt and command.
+type TermCommand struct {
+	Prompt  string // e.g. "$" or "user@host:~$"
+	Command string // the command, with continuation lines
+	Output  string
+}
+
+// promptRE matches a command line of a terminal session, the prompt is a
+// bare "$", "#", "%" or ">", or "user@host:path" followed by "$" or "#",
+// e.g. "$ ls" or "root@host:~# ls". Output lines like "100% done" don't
+// match.
+var promptRE = regexp.MustCompile(`^([$#%>]|[\w.-]+@[\w.-]+:[^\s$#]*[$#])\s+(.*)$`)
+
+var termRE = regexp.MustCompile(`^\.term\s+((?:(?:-steps|-tabwidth\s+\S+)\s+)*)(\S+)$`)
+
+// parseTerm parses a term present directive. Its syntax:
+// .term [-steps] [-tabwidth <n>] <filename>[@rev]
+// The file is a transcript of a terminal session. With -steps the
+// commands are revealed one at a time.
+func parseTerm(ctx *Context, sourceFile string, sourceLine int, cmd string) (Elem, error) {
+	args := termRE.FindStringSubmatch(strings.TrimSpace(cmd))
+	if args == nil {
+		return nil, fmt.Errorf("%s:%d: syntax error for .term invocation", sourceFile, sourceLine)
+	}
+	flags := args[1]
+
+	tabWidth, err := ctx.tabWidthFlag(flags)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%d: %v", sourceFile, sourceLine, err)
+	}
+
+	file, rev := splitRev(args[2])
+	filename := filepath.Join(filepath.Dir(sourceFile), file)
+	text, err := ctx.readFileAt(filename, rev)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%d: %v", sourceFile, sourceLine, err)
+	}
+
+	s := expandTabs(string(text), tabWidth)
+	s = strings.TrimRightFunc(s, unicode.IsSpace)
+
+	return parseTermText(s, strings.Contains(flags, "-steps")), nil
+}
+
+// parseTermText splits the transcript of a terminal session into commands
+// and their output. A command line ending with a backslash continues on
+// the next line. The first prompt decides the prompt character, a bare
+// prompt with another character is output, e.g. "# comment" or "> quote"
+// in a session using "$".
+func parseTermText(text string, steps bool) Term {
+	t := Term{Steps: steps}
+	promptChar := ""
+
+	var cur *TermCommand
+	var output []string
+	flush := func() {
+		if cur != nil {
+			cur.Output = strings.Join(output, "\n")
+			t.Commands = append(t.Commands, *cur)
+		}
+		cur, output = nil, nil
+	}
+
+	lines := strings.Split(text, "\n")
+	for i := 0; i < len(lines); i++ {
+		m := promptRE.FindStringSubmatch(lines[i])
+		if m != nil {
+			last := m[1][len(m[1])-1:]
+			if promptChar == "" {
+				promptChar = last
+			} else if len(m[1]) == 1 && last != promptChar {
+				m = nil
+			}
+		}
+		if m == nil {
+			if cur == nil {
+				cur = &TermCommand{}
+			}
+			output = append(output, lines[i])
+			continue
+		}
+
+		flush()
+		command := m[2]
+		for strings.HasSuffix(command, `\`) && i+1 < len(lines) {
+			i++
+			command += "\n" + lines[i]
+		}
+		cur = &TermCommand{Prompt: m[1], Command: command}
+	}
+	flush()
+
+	return t
+}
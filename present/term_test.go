@@ -0,0 +1,110 @@
+package present
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTermText(t *testing.T) {
+	text := `Last login: Mon
+$ go test ./...
+ok      pkg     0.01s
+user@host:~# docker run \
+  --rm hello
+Hello!
+$ exit`
+
+	want := Term{
+		Steps: true,
+		Commands: []TermCommand{
+			{Output: "Last login: Mon"},
+			{Prompt: "$", Command: "go test ./...", Output: "ok      pkg     0.01s"},
+			{Prompt: "user@host:~#", Command: "docker run \\\n  --rm hello", Output: "Hello!"},
+			{Prompt: "$", Command: "exit"},
+		},
+	}
+
+	got := parseTermText(text, true)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v\nwant %#v", got, want)
+	}
+	if n := got.StepCount(); n != 3 {
+		t.Errorf("got %d steps; want 3", n)
+	}
+}
+
+func TestParseTermTextOutput(t *testing.T) {
+	tests := []struct {
+		text     string
+		commands []TermCommand
+	}{
+		// output lines which look like prompts
+		{
+			"$ make\n100% done\n50%  built\nfoo> bar\n# comment\n> quoted\n% not a prompt",
+			[]TermCommand{{Prompt: "$", Command: "make", Output: "100% done\n50%  built\nfoo> bar\n# comment\n> quoted\n% not a prompt"}},
+		},
+		{
+			"# apt install go\n$ not a prompt\n# go version",
+			[]TermCommand{
+				{Prompt: "#", Command: "apt install go", Output: "$ not a prompt"},
+				{Prompt: "#", Command: "go version"},
+			},
+		},
+		{
+			"user@host:~/src$ ls\nmain.go\n$ pwd\nroot@box:/# id",
+			[]TermCommand{
+				{Prompt: "user@host:~/src$", Command: "ls", Output: "main.go"},
+				{Prompt: "$", Command: "pwd"},
+				{Prompt: "root@box:/#", Command: "id"},
+			},
+		},
+		{
+			"$ echo\n$\n$ok",
+			[]TermCommand{{Prompt: "$", Command: "echo", Output: "$\n$ok"}},
+		},
+	}
+
+	for _, tt := range tests {
+		got := parseTermText(tt.text, false)
+		if !reflect.DeepEqual(got.Commands, tt.commands) {
+			t.Errorf("%q:\ngot  %#v\nwant %#v", tt.text, got.Commands, tt.commands)
+		}
+	}
+}
+
+func TestParseTerm(t *testing.T) {
+	ctx := &Context{
+		ReadFile: func(string) ([]byte, error) { return []byte("$ ls\na\tb\n$ pwd\n"), nil },
+		tabWidth: 4,
+	}
+
+	tests := []struct {
+		cmd    string
+		output string
+		steps  bool
+	}{
+		{".term session.txt", "a   b", false},
+		{".term -tabwidth 2 session.txt", "a b", false},
+		{".term -steps -tabwidth 8 session.txt", "a       b", true},
+		{".term -tabwidth 8 -steps session.txt", "a       b", true},
+	}
+
+	for _, tt := range tests {
+		e, err := parseTerm(ctx, "talk.slide", 1, tt.cmd)
+		if err != nil {
+			t.Errorf("%s: %v", tt.cmd, err)
+			continue
+		}
+
+		term := e.(Term)
+		if len(term.Commands) != 2 || term.Commands[0].Output != tt.output || term.Steps != tt.steps {
+			t.Errorf("%s: got %#v", tt.cmd, term)
+		}
+	}
+
+	for _, cmd := range []string{".term -tabwidth 0 session.txt", ".term -tabwidth session.txt", ".term"} {
+		if _, err := parseTerm(ctx, "talk.slide", 1, cmd); err == nil {
+			t.Errorf("%s: expected error", cmd)
+		}
+	}
+}
@@ -20,7 +20,7 @@ func (v Video) TemplateName() string { return "video" }
 
 func parseVideo(ctx *Context, fileName string, lineno int, text string) (Elem, error) {
 	args := strings.Fields(text)
-	vid := Video{URL: args[1], SourceType: args[2]}
+	vid := Video{URL: ctx.resolveURL(fileName, args[1]), SourceType: args[2]}
 	a, err := parseArgs(fileName, lineno, args[3:])
 	if err != nil {
 		return nil, err
@@ -0,0 +1,548 @@
+package present
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// vt is a minimal terminal emulator, enough to replay recorded terminal
+// sessions: printing, cursor movement, erasing, scrolling and colors.
+// Scroll regions and character sets are not supported.
+type vt struct {
+	w, h  int
+	lines [][]vtCell
+	x, y  int
+	style vtStyle
+
+	hideCursor bool
+	savedX     int
+	savedY     int
+	main       [][]vtCell // main screen while the alternate screen is used
+
+	state  int    // parser state, one of the vtState constants
+	params []byte // parameters of the current control sequence
+
+	dirty map[int]bool // lines changed since the last takeDirty
+}
+
+const (
+	vtGround = iota
+	vtEscape
+	vtCSI
+	vtOSC
+	vtOSCEscape
+	vtCharset
+)
+
+// vtStyle is the graphic rendition of a cell. Colors are -1 for the
+// default, 0-255 for the xterm palette, or vtRGB|0xrrggbb.
+type vtStyle struct {
+	fg, bg                            int
+	bold, dim, italic, underline, rev bool
+}
+
+const vtRGB = 1 << 24
+
+var vtDefaultStyle = vtStyle{fg: -1, bg: -1}
+
+type vtCell struct {
+	r rune
+	s vtStyle
+}
+
+var vtBlank = vtCell{' ', vtDefaultStyle}
+
+func newVT(w, h int) *vt {
+	t := &vt{w: w, h: h, style: vtDefaultStyle, dirty: map[int]bool{}}
+	t.lines = t.blankScreen()
+	return t
+}
+
+func (t *vt) blankLine() []vtCell {
+	l := make([]vtCell, t.w)
+	for i := range l {
+		l[i] = vtBlank
+	}
+	return l
+}
+
+func (t *vt) blankScreen() [][]vtCell {
+	lines := make([][]vtCell, t.h)
+	for i := range lines {
+		lines[i] = t.blankLine()
+	}
+	return lines
+}
+
+func (t *vt) touch(y int) { t.dirty[y] = true }
+
+func (t *vt) touchAll() {
+	for y := 0; y < t.h; y++ {
+		t.dirty[y] = true
+	}
+}
+
+// takeDirty returns the lines changed since the last call in order.
+func (t *vt) takeDirty() []int {
+	var ys []int
+	for y := 0; y < t.h; y++ {
+		if t.dirty[y] {
+			ys = append(ys, y)
+		}
+	}
+	t.dirty = map[int]bool{}
+	return ys
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// Write feeds output of the recorded program to the terminal.
+func (t *vt) Write(s string) {
+	for _, r := range s {
+		switch t.state {
+		case vtGround:
+			t.ground(r)
+		case vtEscape:
+			t.escape(r)
+		case vtCSI:
+			if r >= 0x40 && r <= 0x7e {
+				t.state = vtGround
+				t.csi(r)
+			} else {
+				t.params = append(t.params, byte(r))
+			}
+		case vtOSC:
+			// Operating system commands, e.g. window titles, are ignored.
+			switch r {
+			case 0x07:
+				t.state = vtGround
+			case 0x1b:
+				t.state = vtOSCEscape
+			}
+		case vtOSCEscape:
+			t.state = vtGround
+		case vtCharset:
+			t.state = vtGround
+		}
+	}
+}
+
+func (t *vt) ground(r rune) {
+	switch r {
+	case 0x1b:
+		t.state = vtEscape
+	case '\r':
+		t.x = 0
+	case '\n', '\v', '\f':
+		t.lineFeed()
+	case '\b':
+		if t.x > 0 {
+			t.x--
+		}
+	case '\t':
+		t.x = clamp((t.x/8+1)*8, 0, t.w-1)
+	default:
+		if r < 0x20 || r == 0x7f {
+			return
+		}
+		// The cursor stays after the last column until the next
+		// character is printed.
+		if t.x >= t.w {
+			t.x = 0
+			t.lineFeed()
+		}
+		t.lines[t.y][t.x] = vtCell{r, t.style}
+		t.touch(t.y)
+		t.x++
+	}
+}
+
+func (t *vt) escape(r rune) {
+	t.state = vtGround
+	switch r {
+	case '[':
+		t.state = vtCSI
+		t.params = t.params[:0]
+	case ']':
+		t.state = vtOSC
+	case '(', ')', '*', '+':
+		t.state = vtCharset
+	case '7':
+		t.savedX, t.savedY = t.x, t.y
+	case '8':
+		t.x, t.y = t.savedX, t.savedY
+	case 'D':
+		t.lineFeed()
+	case 'E':
+		t.x = 0
+		t.lineFeed()
+	case 'M':
+		if t.y == 0 {
+			t.scrollDown(1)
+		} else {
+			t.y--
+		}
+	case 'c':
+		*t = *newVT(t.w, t.h)
+		t.touchAll()
+	}
+}
+
+func (t *vt) lineFeed() {
+	if t.y == t.h-1 {
+		t.scrollUp(1)
+	} else {
+		t.y++
+	}
+}
+
+func (t *vt) scrollUp(n int) {
+	n = clamp(n, 0, t.h)
+	for i := 0; i < n; i++ {
+		t.lines = append(t.lines[1:], t.blankLine())
+	}
+	t.touchAll()
+}
+
+func (t *vt) scrollDown(n int) {
+	n = clamp(n, 0, t.h)
+	for i := 0; i < n; i++ {
+		t.lines = append([][]vtCell{t.blankLine()}, t.lines[:t.h-1]...)
+	}
+	t.touchAll()
+}
+
+// erase clears the cells [from, to) of line y.
+func (t *vt) erase(y, from, to int) {
+	from, to = clamp(from, 0, t.w), clamp(to, 0, t.w)
+	for x := from; x < to; x++ {
+		t.lines[y][x] = vtCell{' ', vtStyle{fg: -1, bg: t.style.bg}}
+	}
+	t.touch(y)
+}
+
+func (t *vt) csi(final rune) {
+	s := string(t.params)
+	private := strings.HasPrefix(s, "?")
+	s = strings.TrimLeft(s, "?>=")
+
+	var ps []int
+	for _, p := range strings.Split(s, ";") {
+		n, _ := strconv.Atoi(p)
+		ps = append(ps, n)
+	}
+	// param returns the ith parameter, or def if missing or zero.
+	param := func(i, def int) int {
+		if i < len(ps) && ps[i] != 0 {
+			return ps[i]
+		}
+		return def
+	}
+
+	// The cursor leaves the pending wrap position on any movement.
+	x := clamp(t.x, 0, t.w-1)
+
+	switch final {
+	case 'A':
+		t.y = clamp(t.y-param(0, 1), 0, t.h-1)
+		t.x = x
+	case 'B', 'e':
+		t.y = clamp(t.y+param(0, 1), 0, t.h-1)
+		t.x = x
+	case 'C', 'a':
+		t.x = clamp(x+param(0, 1), 0, t.w-1)
+	case 'D':
+		t.x = clamp(x-param(0, 1), 0, t.w-1)
+	case 'E':
+		t.y = clamp(t.y+param(0, 1), 0, t.h-1)
+		t.x = 0
+	case 'F':
+		t.y = clamp(t.y-param(0, 1), 0, t.h-1)
+		t.x = 0
+	case 'G', '`':
+		t.x = clamp(param(0, 1)-1, 0, t.w-1)
+	case 'd':
+		t.y = clamp(param(0, 1)-1, 0, t.h-1)
+		t.x = x
+	case 'H', 'f':
+		t.y = clamp(param(0, 1)-1, 0, t.h-1)
+		t.x = clamp(param(1, 1)-1, 0, t.w-1)
+	case 'J':
+		switch param(0, 0) {
+		case 0:
+			t.erase(t.y, x, t.w)
+			for y := t.y + 1; y < t.h; y++ {
+				t.erase(y, 0, t.w)
+			}
+		case 1:
+			for y := 0; y < t.y; y++ {
+				t.erase(y, 0, t.w)
+			}
+			t.erase(t.y, 0, x+1)
+		case 2, 3:
+			for y := 0; y < t.h; y++ {
+				t.erase(y, 0, t.w)
+			}
+		}
+	case 'K':
+		switch param(0, 0) {
+		case 0:
+			t.erase(t.y, x, t.w)
+		case 1:
+			t.erase(t.y, 0, x+1)
+		case 2:
+			t.erase(t.y, 0, t.w)
+		}
+	case 'X':
+		t.erase(t.y, x, x+param(0, 1))
+	case 'P':
+		n := clamp(param(0, 1), 0, t.w-x)
+		l := t.lines[t.y]
+		copy(l[x:], l[x+n:])
+		t.erase(t.y, t.w-n, t.w)
+	case '@':
+		n := clamp(param(0, 1), 0, t.w-x)
+		l := t.lines[t.y]
+		copy(l[x+n:], l[x:])
+		t.erase(t.y, x, x+n)
+	case 'L', 'M':
+		n := clamp(param(0, 1), 0, t.h-t.y)
+		rest := append([][]vtCell{}, t.lines[t.y:]...)
+		blank := make([][]vtCell, n)
+		for i := range blank {
+			blank[i] = t.blankLine()
+		}
+		if final == 'L' {
+			rest = append(blank, rest[:len(rest)-n]...)
+		} else {
+			rest = append(rest[n:], blank...)
+		}
+		copy(t.lines[t.y:], rest)
+		t.touchAll()
+	case 'S':
+		t.scrollUp(param(0, 1))
+	case 'T':
+		t.scrollDown(param(0, 1))
+	case 's':
+		t.savedX, t.savedY = t.x, t.y
+	case 'u':
+		t.x, t.y = t.savedX, t.savedY
+	case 'm':
+		t.sgr(ps)
+	case 'h', 'l':
+		if !private {
+			return
+		}
+		set := final == 'h'
+		for _, p := range ps {
+			switch p {
+			case 25:
+				t.hideCursor = !set
+			case 47, 1047, 1049:
+				t.alternateScreen(set)
+			}
+		}
+	}
+}
+
+// alternateScreen switches to or back from the alternate screen used by
+// full screen programs, e.g. editors.
+func (t *vt) alternateScreen(on bool) {
+	if on && t.main == nil {
+		t.main = t.lines
+		t.lines = t.blankScreen()
+	} else if !on && t.main != nil {
+		t.lines = t.main
+		t.main = nil
+	}
+	t.touchAll()
+}
+
+// sgr sets the graphic rendition.
+func (t *vt) sgr(ps []int) {
+	for i := 0; i < len(ps); i++ {
+		p := ps[i]
+		switch {
+		case p == 0:
+			t.style = vtDefaultStyle
+		case p == 1:
+			t.style.bold = true
+		case p == 2:
+			t.style.dim = true
+		case p == 3:
+			t.style.italic = true
+		case p == 4:
+			t.style.underline = true
+		case p == 7:
+			t.style.rev = true
+		case p == 22:
+			t.style.bold, t.style.dim = false, false
+		case p == 23:
+			t.style.italic = false
+		case p == 24:
+			t.style.underline = false
+		case p == 27:
+			t.style.rev = false
+		case p >= 30 && p <= 37:
+			t.style.fg = p - 30
+		case p == 39:
+			t.style.fg = -1
+		case p >= 40 && p <= 47:
+			t.style.bg = p - 40
+		case p == 49:
+			t.style.bg = -1
+		case p >= 90 && p <= 97:
+			t.style.fg = p - 90 + 8
+		case p >= 100 && p <= 107:
+			t.style.bg = p - 100 + 8
+		case p == 38 || p == 48:
+			var c int
+			switch {
+			case i+2 < len(ps) && ps[i+1] == 5:
+				c = clamp(ps[i+2], 0, 255)
+				i += 2
+			case i+4 < len(ps) && ps[i+1] == 2:
+				c = vtRGB | clamp(ps[i+2], 0, 255)<<16 | clamp(ps[i+3], 0, 255)<<8 | clamp(ps[i+4], 0, 255)
+				i += 4
+			default:
+				return
+			}
+			if p == 38 {
+				t.style.fg = c
+			} else {
+				t.style.bg = c
+			}
+		}
+	}
+}
+
+// lineHTML renders line y, with the cursor if cursor is true. Trailing
+// blank cells are omitted.
+func (t *vt) lineHTML(y int, cursor bool) string {
+	cells := t.lines[y]
+	cx := -1
+	if cursor && !t.hideCursor && y == t.y {
+		cx = clamp(t.x, 0, t.w-1)
+	}
+
+	end := len(cells)
+	for end > 0 && end-1 != cx && cells[end-1] == vtBlank {
+		end--
+	}
+
+	var b strings.Builder
+	for x := 0; x < end; {
+		// Group cells of the same style, the cursor is on its own.
+		n := x + 1
+		if x != cx {
+			for n < end && n != cx && cells[n].s == cells[x].s {
+				n++
+			}
+		}
+
+		var text strings.Builder
+		for _, c := range cells[x:n] {
+			text.WriteRune(c.r)
+		}
+
+		attrs := cells[x].s.attrs(x == cx)
+		if attrs == "" {
+			b.WriteString(html.EscapeString(text.String()))
+		} else {
+			fmt.Fprintf(&b, "<span%s>%s</span>", attrs, html.EscapeString(text.String()))
+		}
+		x = n
+	}
+	return b.String()
+}
+
+// attrs returns the html attributes of a span of the style. The 16 basic
+// colors use classes so that themes can change them.
+func (s vtStyle) attrs(cursor bool) string {
+	fg, bg := s.fg, s.bg
+	if s.rev {
+		fg, bg = bg, fg
+		if fg == -1 {
+			fg = vtDefaultBg
+		}
+		if bg == -1 {
+			bg = vtDefaultFg
+		}
+	}
+	if s.bold && fg >= 0 && fg < 8 {
+		fg += 8
+	}
+
+	var class, style []string
+	color := func(prop string, c int) {
+		switch {
+		case c < 0:
+		case c < 16:
+			class = append(class, fmt.Sprintf("ac-%s-%d", prop, c))
+		default:
+			style = append(style, fmt.Sprintf("%s:%s", map[string]string{"fg": "color", "bg": "background-color"}[prop], vtColor(c)))
+		}
+	}
+	color("fg", fg)
+	color("bg", bg)
+
+	if s.bold {
+		class = append(class, "ac-bold")
+	}
+	if s.dim {
+		class = append(class, "ac-dim")
+	}
+	if s.italic {
+		class = append(class, "ac-italic")
+	}
+	if s.underline {
+		class = append(class, "ac-underline")
+	}
+	if cursor {
+		class = append(class, "ac-cursor")
+	}
+
+	var attrs string
+	if len(class) > 0 {
+		attrs += fmt.Sprintf(` class="%s"`, strings.Join(class, " "))
+	}
+	if len(style) > 0 {
+		attrs += fmt.Sprintf(` style="%s"`, strings.Join(style, ";"))
+	}
+	return attrs
+}
+
+// default colors for reverse video, in the xterm palette
+const (
+	vtDefaultFg = 15
+	vtDefaultBg = 0
+)
+
+// vtColor returns the css color of an xterm 256 color or rgb color.
+func vtColor(c int) string {
+	if c&vtRGB != 0 {
+		return fmt.Sprintf("#%06x", c&^vtRGB)
+	}
+	if c >= 232 {
+		v := 8 + (c-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", v, v, v)
+	}
+	c -= 16
+	level := func(v int) int {
+		if v == 0 {
+			return 0
+		}
+		return 55 + v*40
+	}
+	return fmt.Sprintf("#%02x%02x%02x", level(c/36), level(c/6%6), level(c%6))
+}
@@ -0,0 +1,97 @@
+package present
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultTabWidth is the tab width of code and pre text, unless set by the
+// .tabwidth header or a -tabwidth flag.
+const defaultTabWidth = 4
+
+var tabWidthFlagRE = regexp.MustCompile(`-tabwidth\s+(\S+)`)
+
+// parseTabWidth parses a tab width, which is between 1 and 16.
+func parseTabWidth(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > 16 {
+		return 0, fmt.Errorf("invalid tab width %q, must be 1-16", s)
+	}
+	return n, nil
+}
+
+// tabWidthFlag returns the tab width of the -tabwidth flag in flags, or
+// the tab width of the document if there is none.
+func (ctx *Context) tabWidthFlag(flags string) (int, error) {
+	if m := tabWidthFlagRE.FindStringSubmatch(flags); m != nil {
+		return parseTabWidth(m[1])
+	}
+	return ctx.tabWidth, nil
+}
+
+// expandTabs replaces tabs in s with spaces up to the next tab stop,
+// browsers treat tabs badly.
+func expandTabs(s string, width int) string {
+	if width <= 0 {
+		width = defaultTabWidth
+	}
+	if !strings.Contains(s, "\t") {
+		return s
+	}
+
+	var b strings.Builder
+	col := 0
+	for _, r := range s {
+		switch r {
+		case '\t':
+			n := width - col%width
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+		case '\n':
+			b.WriteRune(r)
+			col = 0
+		default:
+			b.WriteRune(r)
+			col++
+		}
+	}
+	return b.String()
+}
+
+// commonIndent returns the number of leading spaces shared by all
+// non-blank lines.
+func commonIndent(lines []string) int {
+	indent := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " "))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent < 0 {
+		return 0
+	}
+	return indent
+}
+
+// normalizeText expands tabs, trims trailing white space and removes the
+// common indentation of the lines of s.
+func normalizeText(s string, tabWidth int) string {
+	lines := strings.Split(expandTabs(s, tabWidth), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t\r")
+	}
+
+	indent := commonIndent(lines)
+	for i, l := range lines {
+		if len(l) >= indent {
+			lines[i] = l[indent:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
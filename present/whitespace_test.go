@@ -0,0 +1,36 @@
+package present
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPreTabWidth(t *testing.T) {
+	ctx := &Context{}
+	doc, err := ctx.Parse(strings.NewReader("Title\n.tabwidth 2\n\n* Slide\n\n  #lang txt\n    a\t# a  \n    \tb\n\ntext\n\n  #lang txt -tabwidth 8\n  \tc\n"), "talk.slide", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, e := range doc.Sections[0].Elem {
+		got = append(got, e.(Text).Lines[0])
+	}
+	want := []string{"a # a\n  b", "text", "c"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q; want %q", got, want)
+	}
+
+	// the tab width belongs to the document, not to the caller's Context
+	if ctx.tabWidth != 0 {
+		t.Errorf("Parse changed the tab width of the Context to %d", ctx.tabWidth)
+	}
+
+	if s := expandTabs("ab\tc\t\td", 4); s != "ab  c       d" {
+		t.Errorf("got %q", s)
+	}
+
+	if _, err := ctx.Parse(strings.NewReader("Title\n.tabwidth x\n"), "talk.slide", 0); err == nil {
+		t.Error("expected error for invalid tab width")
+	}
+}
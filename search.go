@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cj1128/mypresent/present"
+	"github.com/pkg/errors"
+)
+
+// searchDeck is the searchable content of a slide deck,
+// field names are kept short to make the prebuilt index compact
+type searchDeck struct {
+	Title  string         `json:"t"`
+	Path   string         `json:"p"`
+	Slides []*searchSlide `json:"s"`
+}
+
+// searchSlide is the searchable content of one slide
+type searchSlide struct {
+	Number int    `json:"n"` // slide number used in the url hash, title slide is 1
+	Title  string `json:"t"`
+	Text   string `json:"x"`
+}
+
+type searchResult struct {
+	Deck    string `json:"deck"`
+	Path    string `json:"path"` // path with the slide number hash
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+}
+
+// maximum number of results returned by the search endpoint
+const maxSearchResults = 50
+
+// searchDeckCache holds the parsed content of slides by their source,
+// the search endpoint of serve is queried on every keystroke
+var searchDeckCache = struct {
+	sync.Mutex
+	m map[string]*searchCacheEntry
+}{m: make(map[string]*searchCacheEntry)}
+
+type searchCacheEntry struct {
+	modTime time.Time // of the slide file
+	deck    *searchDeck
+}
+
+// buildSearchIndex parses every slide in full mode, slides which are not
+// modified since the last call are not parsed again
+func buildSearchIndex(slides []*slideData) ([]*searchDeck, error) {
+	searchDeckCache.Lock()
+	defer searchDeckCache.Unlock()
+
+	var result []*searchDeck
+	seen := make(map[string]bool)
+
+	for _, slide := range slides {
+		info, err := os.Stat(filepath.Join(opts.contentBase, slide.Source))
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not stat slide: %s", slide.Path)
+		}
+
+		entry := searchDeckCache.m[slide.Source]
+		if entry == nil || !entry.modTime.Equal(info.ModTime()) {
+			deck, err := parseSearchDeck(slide)
+			if err != nil {
+				return nil, err
+			}
+
+			entry = &searchCacheEntry{info.ModTime(), deck}
+			searchDeckCache.m[slide.Source] = entry
+		}
+
+		seen[slide.Source] = true
+
+		// the path differs between serve and build
+		result = append(result, &searchDeck{
+			Title:  entry.deck.Title,
+			Path:   slide.Path,
+			Slides: entry.deck.Slides,
+		})
+	}
+
+	for source := range searchDeckCache.m {
+		if !seen[source] {
+			delete(searchDeckCache.m, source)
+		}
+	}
+
+	return result, nil
+}
+
+func parseSearchDeck(slide *slideData) (*searchDeck, error) {
+	doc, err := parseSlide(slide.Source, present.FullMode)
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse slide: %s", slide.Path)
+	}
+
+	deck := &searchDeck{
+		Title: doc.Title,
+		Path:  slide.Path,
+	}
+
+	title := []string{doc.Title, doc.Subtitle}
+	title = append(title, doc.TitleNotes...)
+	title = append(title, doc.Misc...)
+
+	deck.Slides = append(deck.Slides, &searchSlide{
+		Number: 1,
+		Title:  doc.Title,
+		Text:   joinText(title),
+	})
+
+	for _, s := range doc.Sections {
+		deck.Slides = append(deck.Slides, &searchSlide{
+			Number: s.Number[0] + 1,
+			Title:  s.Title,
+			Text:   joinText(sectionText(s)),
+		})
+	}
+
+	return deck, nil
+}
+
+// sectionText returns all searchable text of the section,
+// including its subsections and notes
+func sectionText(s present.Section) []string {
+	result := []string{s.Title}
+
+	for _, e := range s.Elem {
+		switch e := e.(type) {
+		case present.Section:
+			result = append(result, sectionText(e)...)
+		case present.Text:
+			result = append(result, e.Lines...)
+		case present.List:
+			result = append(result, e.Bullet...)
+		case present.Code:
+			result = append(result, string(e.Raw))
+		case present.Diff:
+			result = append(result, string(e.Raw))
+		case present.Output:
+			result = append(result, e.Command, e.Text)
+		case present.Term:
+			for _, c := range e.Commands {
+				result = append(result, c.Command, c.Output)
+			}
+		case present.Caption:
+			result = append(result, e.Text)
+		case present.Link:
+			result = append(result, e.Label)
+		}
+	}
+
+	return append(result, s.Notes...)
+}
+
+// joinText collapses all whitespace so the index stays small
+func joinText(lines []string) string {
+	return strings.Join(strings.Fields(strings.Join(lines, " ")), " ")
+}
+
+// searchIndex returns results for slides containing all words of query,
+// case insensitive
+func searchIndex(decks []*searchDeck, query string) []*searchResult {
+	words := strings.Fields(strings.ToLower(query))
+	result := make([]*searchResult, 0)
+
+	if len(words) == 0 {
+		return result
+	}
+
+	for _, deck := range decks {
+	Slides:
+		for _, s := range deck.Slides {
+			text := strings.ToLower(deck.Title + " " + s.Title + " " + s.Text)
+
+			for _, w := range words {
+				if !strings.Contains(text, w) {
+					continue Slides
+				}
+			}
+
+			result = append(result, &searchResult{
+				Deck:    deck.Title,
+				Path:    deck.Path + "#" + strconv.Itoa(s.Number),
+				Title:   s.Title,
+				Snippet: snippet(s.Text, words[0]),
+			})
+
+			if len(result) == maxSearchResults {
+				return result
+			}
+		}
+	}
+
+	return result
+}
+
+// snippet returns the text around the first occurrence of word
+func snippet(text, word string) string {
+	const radius = 60
+
+	// lowercasing may change the byte length of a rune but not the number
+	// of runes, so the match is located in runes of the lowercased text
+	runes := []rune(text)
+	lower := strings.ToLower(text)
+	i := strings.Index(lower, word)
+	if i < 0 {
+		i = 0
+	}
+
+	i = utf8.RuneCountInString(lower[:i])
+
+	lo, hi := i-radius, i+len([]rune(word))+radius
+	prefix, suffix := "…", "…"
+
+	if lo <= 0 {
+		lo, prefix = 0, ""
+	}
+
+	if hi >= len(runes) {
+		hi, suffix = len(runes), ""
+	}
+
+	return prefix + string(runes[lo:hi]) + suffix
+}
+
+func handleSearch(w http.ResponseWriter, r *http.Request) {
+	id, err := scanDir(".")
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	decks, err := buildSearchIndex(getAllSlides(id))
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(searchIndex(decks, r.URL.Query().Get("q")))
+}
+
+// getSearchHTML renders the search page
+// if indexURL is empty, the page queries the search endpoint of the server,
+// otherwise it loads the prebuilt index and searches in the browser
+func getSearchHTML(indexURL string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	if err := searchTemplate.Execute(buf, struct {
+		IndexURL string
+	}{indexURL}); err != nil {
+		return nil, errors.Wrap(err, "could not execute template")
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildSearchIndexCache(t *testing.T) {
+	writeContent(t, map[string]string{
+		"talk.slide": "Talk\n\n* Goroutines\n\nGo statements start goroutines.\n",
+	})
+
+	slides := []*slideData{{Name: "Talk", Path: "talk.slide", Source: "talk.slide"}}
+	file := filepath.Join(opts.contentBase, "talk.slide")
+	mtime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	search := func(query string) int {
+		if err := os.Chtimes(file, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+
+		decks, err := buildSearchIndex(slides)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return len(searchIndex(decks, query))
+	}
+
+	if n := search("goroutines"); n != 1 {
+		t.Errorf("got %d results; want 1", n)
+	}
+
+	// not parsed again while the modification time is the same
+	if err := ioutil.WriteFile(file, []byte("Talk\n\n* Channels\n\nChannels connect goroutines.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := search("channels"); n != 0 {
+		t.Errorf("got %d results from the cache; want 0", n)
+	}
+
+	mtime = mtime.Add(time.Second)
+
+	if n := search("channels"); n != 1 {
+		t.Errorf("got %d results after modification; want 1", n)
+	}
+
+	// removed slides are dropped from the cache
+	if _, err := buildSearchIndex(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(searchDeckCache.m); n != 0 {
+		t.Errorf("got %d cached decks; want 0", n)
+	}
+}
+
+func TestSearchIndex(t *testing.T) {
+	decks := []*searchDeck{{
+		Title: "Concurrency",
+		Path:  "go/talk.html",
+		Slides: []*searchSlide{
+			{Number: 1, Title: "Concurrency", Text: "Concurrency is not parallelism"},
+			{Number: 2, Title: "Goroutines", Text: "A goroutine is a lightweight thread"},
+		},
+	}}
+
+	tests := []struct {
+		query string
+		paths []string
+	}{
+		{"", nil},
+		{"GOROUTINE", []string{"go/talk.html#2"}},
+		{"concurrency", []string{"go/talk.html#1", "go/talk.html#2"}},
+		{"goroutine thread", []string{"go/talk.html#2"}},
+		{"goroutine parallelism", nil},
+	}
+
+	for _, tt := range tests {
+		var paths []string
+		for _, r := range searchIndex(decks, tt.query) {
+			paths = append(paths, r.Path)
+		}
+
+		if len(paths) != len(tt.paths) {
+			t.Errorf("%q: got %v; want %v", tt.query, paths, tt.paths)
+			continue
+		}
+
+		for i := range paths {
+			if paths[i] != tt.paths[i] {
+				t.Errorf("%q: got %v; want %v", tt.query, paths, tt.paths)
+				break
+			}
+		}
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	long := strings.Repeat("x", 70)
+
+	tests := []struct {
+		text, word, want string
+	}{
+		{"Concurrency is not parallelism", "not", "Concurrency is not parallelism"},
+		{"missing", "word", "missing"},
+		{long + " Go " + long, "go", "…" + long[11:] + " Go " + long[11:] + "…"},
+		// Ⱥ is 2 bytes but its lowercase ⱥ is 3 bytes
+		{"ȺȺȺ Go", "go", "ȺȺȺ Go"},
+		{strings.Repeat("Ⱥ", 70) + " Go", "go", "…" + strings.Repeat("Ⱥ", 59) + " Go"},
+	}
+
+	for _, tt := range tests {
+		if got := snippet(tt.text, tt.word); got != tt.want {
+			t.Errorf("snippet(%q, %q) = %q; want %q", tt.text, tt.word, got, tt.want)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 	"time"
@@ -19,7 +20,12 @@ import (
 )
 
 func serveContent() {
-	http.HandleFunc("/static/", func(w http.ResponseWriter, r *http.Request) {
+	// serve always shows drafts and scheduled slides, with a badge
+	opts.includeDrafts = true
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/static/", func(w http.ResponseWriter, r *http.Request) {
 		path := strings.TrimPrefix(r.URL.Path, "/static/")
 		content, err := getAsset(path)
 
@@ -31,14 +37,29 @@ func serveContent() {
 		http.ServeContent(w, r, path, time.Now(), bytes.NewReader(content))
 	})
 
-	http.HandleFunc("/", mainHandler)
+	mux.HandleFunc("/", mainHandler)
+
+	if opts.basePath == "/" {
+		http.Handle("/", mux)
+	} else {
+		http.Handle(opts.basePath, http.StripPrefix(strings.TrimSuffix(opts.basePath, "/"), mux))
+		http.Handle("/", http.RedirectHandler(opts.basePath, http.StatusFound))
+	}
 
-	golog.Infof("server started, port: %d, host: %s", opts.port, opts.host)
+	golog.Infof("server started, port: %d, host: %s, base path: %s", opts.port, opts.host, opts.basePath)
 
 	if opts.notesEnabled {
 		golog.Info("notes are enabled, press 'N' from the browser to display them.")
 	}
 
+	if opts.playEnabled {
+		golog.Warn("play is enabled, anyone who can reach the server can run code on this machine.")
+
+		if !playMemoryLimitSupported() {
+			golog.Warnf("the memory limit of play is not supported on %s, only the go runtime soft limit applies.", runtime.GOOS)
+		}
+	}
+
 	golog.Fatal(http.ListenAndServe(fmt.Sprintf("%s:%d", opts.host, opts.port), nil))
 }
 
@@ -58,11 +79,57 @@ func mainHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if path == "/_play/run" && opts.playEnabled {
+		handlePlay(w, r)
+		return
+	}
+
+	if path == "/search" {
+		handleSearch(w, r)
+		return
+	}
+
+	if path == "/search.html" {
+		content, err := getSearchHTML("")
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Write(content)
+		return
+	}
+
 	if isSlide(path) {
 		handleSlide(w, r)
 		return
 	}
 
+	if isGeneratedCoverCard(path) {
+		png := strings.HasSuffix(path, ".png")
+		content, err := getCoverCard(slidePathOfCoverCard(path), png)
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if png {
+			w.Header().Set("Content-Type", "image/png")
+		} else {
+			w.Header().Set("Content-Type", "image/svg+xml")
+		}
+		w.Write(content)
+		return
+	}
+
+	if strings.HasSuffix(path, "/") && !hasIndexHTML(path) {
+		if handleDirIndex(w, r) || handleListing(w, r) {
+			return
+		}
+	}
+
 	if fileExists(filepath.Join(opts.contentBase, r.URL.Path)) {
 		http.ServeFile(w, r, filepath.Join(opts.contentBase, r.URL.Path))
 		return
@@ -72,7 +139,7 @@ func mainHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func handleSlide(w http.ResponseWriter, r *http.Request) {
-	content, err := getSlideHTML(r.URL.Path)
+	content, err := getSlideHTML(r.URL.Path, r.URL.Path)
 
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -82,20 +149,30 @@ func handleSlide(w http.ResponseWriter, r *http.Request) {
 	w.Write(content)
 }
 
-func getSlideHTML(path string) ([]byte, error) {
+// path is relative to `contentBase`, urlPath is the url path of the page
+func getSlideHTML(path, urlPath string) ([]byte, error) {
 	doc, err := parseSlide(path, present.FullMode)
 
 	if err != nil {
 		return nil, errors.Wrap(err, "could not parse slide")
 	}
 
+	meta, err := newSlideMeta(doc, urlPath)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "could not generate metadata")
+	}
+
 	buf := &bytes.Buffer{}
 
 	err = slideTemplate.Execute(buf, struct {
 		*present.Doc
 		Template     *template.Template
 		NotesEnabled bool
-	}{doc, slideTemplate, opts.notesEnabled})
+		PlayEnabled  bool
+		Status       string
+		Meta         *slideMeta
+	}{doc, slideTemplate, opts.notesEnabled, opts.playEnabled, doc.StatusAt(time.Now()), meta})
 
 	return buf.Bytes(), err
 }
@@ -105,16 +182,41 @@ func isSlide(path string) bool {
 }
 
 type slideData struct {
-	Name  string
-	Cover string
-	Path  string
-	Time  time.Time
+	Name   string
+	Cover  string
+	Thumb  string // thumbnail of the cover
+	Path   string // url path of the slide
+	Source string // path of the slide file, relative to `contentBase`
+	Time   time.Time
+	Tags   []string
+	Event  string
+	Author string
+	Desc   string // subtitle of the slide
+	Status string // status at the time of scanning, see present.Doc.StatusAt
+}
+
+// Hidden reports whether the slide is a draft or not published yet
+func (s *slideData) Hidden() bool {
+	return s.Status == present.StatusDraft || s.Status == present.StatusScheduled
 }
 
 type indexData struct {
 	Name     string // name of the directory
+	Path     string // url path of the directory, e.g. `2019/talk/`, empty for top level
 	Slides   []*slideData
 	Children []*indexData
+	*dirInfo
+}
+
+// HasSlides reports whether there are slides in the directory or its children
+func (id *indexData) HasSlides() bool {
+	found := false
+
+	id.walk(func(c *indexData) {
+		found = found || len(c.Slides) > 0
+	})
+
+	return found
 }
 
 func handleIndex(w http.ResponseWriter, r *http.Request) {
@@ -151,6 +253,80 @@ func getAllSlides(id *indexData) []*slideData {
 	return result
 }
 
+// handleDirIndex serves the index page of a content directory
+// returns false if the directory does not exist or has no slides
+func handleDirIndex(w http.ResponseWriter, r *http.Request) bool {
+	id, err := scanDir(".")
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return true
+	}
+
+	chain := id.findDir(r.URL.Path)
+	if chain == nil || !chain[len(chain)-1].HasSlides() {
+		return false
+	}
+
+	content, err := renderIndex(newDirPage(id, chain))
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return true
+	}
+
+	w.Write(content)
+	return true
+}
+
+// newDirPage returns the index page of the last directory in chain
+func newDirPage(id *indexData, chain []*indexData) *indexPage {
+	dir := chain[len(chain)-1]
+
+	return &indexPage{
+		Title:       dir.Title,
+		Index:       id,
+		Dir:         dir,
+		Breadcrumbs: chain[:len(chain)-1],
+		All:         dir.Slides,
+		catalog:     newCatalog(getAllSlides(id)),
+	}
+}
+
+// handleListing serves tag, event and year pages
+// returns false if there is no listing for the path
+func handleListing(w http.ResponseWriter, r *http.Request) bool {
+	id, err := scanDir(".")
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return true
+	}
+
+	allSlides := getAllSlides(id)
+	c := newCatalog(allSlides)
+
+	title, l := c.find(strings.TrimPrefix(r.URL.Path, "/"))
+	if l == nil {
+		return false
+	}
+
+	content, err := renderIndex(&indexPage{
+		Title:   title,
+		Index:   id,
+		All:     l.Slides,
+		catalog: c,
+	})
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return true
+	}
+
+	w.Write(content)
+	return true
+}
+
 func getIndexHTML() ([]byte, error) {
 	id, err := scanDir(".")
 
@@ -160,12 +336,18 @@ func getIndexHTML() ([]byte, error) {
 
 	allSlides := getAllSlides(id)
 
+	return renderIndex(&indexPage{
+		Index:   id,
+		Dir:     id,
+		All:     allSlides,
+		catalog: newCatalog(allSlides),
+	})
+}
+
+func renderIndex(page *indexPage) ([]byte, error) {
 	buf := &bytes.Buffer{}
 
-	if err := indexTemplate.Execute(buf, struct {
-		All   []*slideData
-		Index *indexData
-	}{allSlides, id}); err != nil {
+	if err := indexTemplate.Execute(buf, page); err != nil {
 		return nil, errors.Wrap(err, "could not execute template")
 	}
 
@@ -175,9 +357,24 @@ func getIndexHTML() ([]byte, error) {
 // dir is relative to `contentBase`
 // top level Name of indexData is `.`
 func scanDir(dir string) (*indexData, error) {
+	info, err := readDirInfo(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
 	result := &indexData{
 		Name:     filepath.Base(dir),
 		Children: make([]*indexData, 0),
+		dirInfo:  info,
+	}
+
+	if dir != "." {
+		result.Path = filepath.ToSlash(dir) + "/"
+	}
+
+	if result.Title == "" {
+		result.Title = result.Name
 	}
 
 	files, err := ioutil.ReadDir(path.Join(opts.contentBase, dir))
@@ -194,6 +391,10 @@ func scanDir(dir string) (*indexData, error) {
 				return nil, err
 			}
 
+			if data.Hidden() && !opts.includeDrafts {
+				continue
+			}
+
 			result.Slides = append(result.Slides, data)
 		}
 
@@ -210,6 +411,8 @@ func scanDir(dir string) (*indexData, error) {
 		// ignore other files
 	}
 
+	result.sortSlides()
+
 	return result, nil
 }
 
@@ -221,7 +424,26 @@ func parseSlide(fp string, mode present.ParseMode) (*present.Doc, error) {
 		return nil, errors.Wrapf(err, "could not open file: %s", fp)
 	}
 
-	return present.Parse(f, path.Join(opts.contentBase, fp), mode)
+	ctx := &present.Context{
+		ReadFile:      ioutil.ReadFile,
+		ReadFileAt:    readFileAt,
+		RunCommand:    runCommand,
+		ResolveURL:    resolveURL,
+		ImageVariants: imageVariants,
+	}
+
+	doc, err := ctx.Parse(f, path.Join(opts.contentBase, fp), mode)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// use the generated cover card if there is no cover
+	if doc.Cover == "" {
+		doc.Cover = siteURL(coverCardPath(fp))
+	}
+
+	return doc, nil
 }
 
 // fp is relative to contentBase
@@ -233,9 +455,16 @@ func parseIndexSlide(fp string) (*slideData, error) {
 	}
 
 	return &slideData{
-		Name:  doc.Title,
-		Cover: doc.Cover,
-		Path:  fp,
-		Time:  doc.Time,
+		Name:   doc.Title,
+		Cover:  doc.Cover,
+		Thumb:  thumbnail(doc.Cover),
+		Path:   fp,
+		Source: fp,
+		Time:   doc.Time,
+		Tags:   doc.Tags,
+		Event:  doc.Event,
+		Author: doc.Author,
+		Desc:   doc.Subtitle,
+		Status: doc.StatusAt(time.Now()),
 	}, nil
 }
@@ -0,0 +1,109 @@
+// Replays terminal recordings of `.asciicast`. The recording is replayed
+// when the slide is rendered, the page only has the html of the changed
+// lines of each frame, the final frame is shown until it's played.
+
+(function() {
+  'use strict';
+
+  function formatTime(seconds) {
+    var s = Math.round(seconds);
+    var m = Math.floor(s / 60);
+    s = s % 60;
+    return m + ':' + (s < 10 ? '0' : '') + s;
+  }
+
+  function setupPlayer(el) {
+    var data = JSON.parse(el.querySelector('.asciicast-data').textContent);
+    var lines = el.querySelectorAll('.asciicast-line');
+    var duration = parseFloat(el.getAttribute('data-duration')) || 0;
+
+    var final = Array.prototype.map.call(lines, function(line) {
+      return line.innerHTML;
+    });
+
+    var timer = null;
+    var next = -1; // index of the next frame, -1 before the first play
+    var played = 0; // seconds played before the last resume
+    var resumed = 0;
+
+    var button = document.createElement('button');
+    button.className = 'asciicast-button';
+    el.appendChild(button);
+
+    function setLabel(playing) {
+      button.textContent = playing ? '❚❚' : '▶ ' + formatTime(duration);
+    }
+
+    function setLine(n, html) {
+      if (lines[n]) lines[n].innerHTML = html;
+    }
+
+    function tick() {
+      var now = played + (performance.now() - resumed) / 1000;
+
+      while (next < data.frames.length && data.frames[next].t <= now) {
+        data.frames[next].l.forEach(function(l) {
+          setLine(l.n, l.h);
+        });
+        next++;
+      }
+
+      if (next >= data.frames.length) {
+        timer = null;
+        next = -1;
+        setLabel(false);
+        return;
+      }
+
+      timer = setTimeout(tick, (data.frames[next].t - now) * 1000);
+    }
+
+    function play() {
+      if (next === -1) {
+        data.initial.forEach(function(html, n) {
+          setLine(n, html);
+        });
+        next = 0;
+        played = 0;
+      }
+
+      resumed = performance.now();
+      setLabel(true);
+      tick();
+    }
+
+    function pause() {
+      clearTimeout(timer);
+      timer = null;
+      played += (performance.now() - resumed) / 1000;
+      setLabel(false);
+    }
+
+    // printing always shows the final frame
+    function reset() {
+      clearTimeout(timer);
+      timer = null;
+      next = -1;
+      final.forEach(function(html, n) {
+        setLine(n, html);
+      });
+      setLabel(false);
+    }
+
+    window.addEventListener('beforeprint', reset, false);
+
+    button.addEventListener('click', function(event) {
+      event.stopPropagation();
+      timer ? pause() : play();
+    }, false);
+
+    setLabel(false);
+  }
+
+  document.addEventListener('DOMContentLoaded', function() {
+    var els = document.querySelectorAll('div.asciicast');
+    for (var i = 0; i < els.length; i++) {
+      setupPlayer(els[i]);
+    }
+  }, false);
+})();
@@ -0,0 +1,125 @@
+// Runs `.play` code blocks with `serve --play`, the code is posted to
+// `_play/run` and its output is streamed back as newline delimited json.
+// `basePath` is set by slide.tmpl
+
+(function() {
+  'use strict';
+
+  var RUN_URL = (window.basePath || '/') + '_play/run';
+
+  // hidden prefix and suffix pres are part of the program
+  function getCode(el) {
+    var pres = el.querySelectorAll('pre');
+    var code = '';
+    for (var i = 0; i < pres.length; i++) {
+      code += pres[i].textContent;
+    }
+    return code;
+  }
+
+  function button(label, onClick) {
+    var el = document.createElement('button');
+    el.textContent = label;
+    el.addEventListener('click', onClick, false);
+    return el;
+  }
+
+  function setupPlayground(el) {
+    var output = null;
+    var controller = null;
+
+    function append(kind, text) {
+      var span = document.createElement('span');
+      span.className = kind;
+      span.textContent = text;
+      output.querySelector('pre').appendChild(span);
+    }
+
+    function handleEvent(event) {
+      if (event.kind !== 'exit') {
+        append(event.kind, event.body);
+        return;
+      }
+
+      append('exit', '\nProgram exited' + (event.body ? ': ' + event.body : '.'));
+    }
+
+    function close() {
+      if (controller) controller.abort();
+      if (output) output.parentNode.removeChild(output);
+      output = null;
+      controller = null;
+    }
+
+    function run() {
+      close();
+
+      var code = getCode(el);
+
+      output = document.createElement('div');
+      output.className = 'output';
+      output.appendChild(document.createElement('pre'));
+
+      var buttons = document.createElement('div');
+      buttons.className = 'buttons';
+      buttons.appendChild(button('Close', close));
+      output.appendChild(buttons);
+
+      el.appendChild(output);
+
+      controller = new AbortController();
+
+      // the custom header keeps other sites from running code
+      fetch(RUN_URL, {
+        method: 'POST',
+        headers: {'X-Play': '1'},
+        body: code,
+        signal: controller.signal,
+      })
+        .then(function(resp) {
+          if (!resp.ok) {
+            return resp.text().then(function(text) {
+              append('error', text);
+            });
+          }
+
+          var reader = resp.body.getReader();
+          var decoder = new TextDecoder();
+          var buf = '';
+
+          function read() {
+            return reader.read().then(function(result) {
+              if (result.done) return;
+
+              buf += decoder.decode(result.value, {stream: true});
+
+              var lines = buf.split('\n');
+              buf = lines.pop();
+              lines.forEach(function(line) {
+                if (line) handleEvent(JSON.parse(line));
+              });
+
+              return read();
+            });
+          }
+
+          return read();
+        })
+        .catch(function(err) {
+          if (err.name !== 'AbortError' && output) append('error', String(err));
+        });
+    }
+
+    var buttons = document.createElement('div');
+    buttons.className = 'buttons';
+    buttons.appendChild(button('Run', run));
+    el.appendChild(buttons);
+  }
+
+  document.addEventListener('DOMContentLoaded', function() {
+    var els = document.querySelectorAll('div.playground');
+    for (var i = 0; i < els.length; i++) {
+      setupPlayground(els[i]);
+    }
+  }, false);
+})();
@@ -0,0 +1,138 @@
+// Search slides, results link to the exact slide.
+//
+// `searchIndexURL` and `basePath` are set by search.tmpl. If the index url
+// is empty, we query the `search` endpoint of the server, otherwise we load
+// the prebuilt index and search in the browser.
+
+var MAX_RESULTS = 50;
+
+var searchIndex = null;
+
+function getQuery() {
+  var match = /[?&]q=([^&]*)/.exec(location.search);
+  return match ? decodeURIComponent(match[1].replace(/\+/g, ' ')) : '';
+}
+
+function fetchJSON(url, callback) {
+  var xhr = new XMLHttpRequest();
+  xhr.open('GET', url);
+  xhr.onload = function() {
+    if (xhr.status == 200) callback(JSON.parse(xhr.responseText));
+  };
+  xhr.send();
+}
+
+// snippet returns the text around the first occurrence of word
+function snippet(text, word) {
+  var radius = 60;
+
+  // lowercasing may change the length of a character, e.g. 'İ', so each
+  // character is lowercased alone and offsets map back into text
+  var lower = '';
+  var offsets = [];
+  for (var k = 0; k < text.length; ) {
+    var c = String.fromCodePoint(text.codePointAt(k));
+    var l = c.toLowerCase();
+    for (var m = 0; m < l.length; m++) offsets.push(k);
+    lower += l;
+    k += c.length;
+  }
+  offsets.push(text.length);
+
+  var i = Math.max(lower.indexOf(word), 0);
+  var lo = offsets[i] - radius;
+  var hi = offsets[Math.min(i + word.length, lower.length)] + radius;
+  var prefix = '…';
+  var suffix = '…';
+
+  if (lo <= 0) {
+    lo = 0;
+    prefix = '';
+  }
+  if (hi >= text.length) {
+    hi = text.length;
+    suffix = '';
+  }
+
+  return prefix + text.substring(lo, hi) + suffix;
+}
+
+// search mirrors `searchIndex` in search.go
+function search(decks, query) {
+  var words = query.toLowerCase().split(/\s+/).filter(Boolean);
+  var result = [];
+
+  if (words.length == 0) return result;
+
+  for (var i = 0; i < decks.length; i++) {
+    var deck = decks[i];
+
+    for (var j = 0; j < deck.s.length; j++) {
+      var s = deck.s[j];
+      var text = (deck.t + ' ' + s.t + ' ' + s.x).toLowerCase();
+
+      var matched = words.every(function(w) {
+        return text.indexOf(w) >= 0;
+      });
+      if (!matched) continue;
+
+      result.push({
+        deck: deck.t,
+        path: deck.p + '#' + s.n,
+        title: s.t,
+        snippet: snippet(s.x, words[0]),
+      });
+
+      if (result.length == MAX_RESULTS) return result;
+    }
+  }
+
+  return result;
+}
+
+function renderResults(results) {
+  var ul = document.querySelector('ul.results');
+  ul.innerHTML = '';
+
+  if (results.length == 0) {
+    var li = document.createElement('li');
+    li.className = 'empty';
+    li.textContent = 'No results.';
+    ul.appendChild(li);
+    return;
+  }
+
+  results.forEach(function(r) {
+    var li = document.createElement('li');
+
+    var a = document.createElement('a');
+    a.href = basePath + r.path;
+    a.textContent = r.deck + (r.title != r.deck ? ' › ' + r.title : '');
+    li.appendChild(a);
+
+    var p = document.createElement('p');
+    p.textContent = r.snippet;
+    li.appendChild(p);
+
+    ul.appendChild(li);
+  });
+}
+
+function handleSearchLoaded() {
+  var query = getQuery();
+  document.querySelector('form.search input').value = query;
+
+  if (!query) return;
+
+  if (!searchIndexURL) {
+    fetchJSON(basePath + 'search?q=' + encodeURIComponent(query), renderResults);
+    return;
+  }
+
+  fetchJSON(searchIndexURL, function(decks) {
+    searchIndex = decks;
+    renderResults(search(searchIndex, query));
+  });
+}
+
+document.addEventListener('DOMContentLoaded', handleSearchLoaded, false);
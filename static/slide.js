@@ -2,7 +2,8 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-var PERMANENT_URL_PREFIX = '/static/';
+// `basePath` is set by slide.tmpl
+var PERMANENT_URL_PREFIX = (window.basePath || '/') + 'static/';
 
 var SLIDE_CLASSES = ['far-past', 'past', 'current', 'next', 'far-next'];
 
@@ -10,6 +11,9 @@ var PM_TOUCH_SENSITIVITY = 15;
 
 var curSlide;
 
+// highlight step of code blocks in the current slide, 0 means no step
+var curStep = 0;
+
 /* ---------------------------------------------------------------------- */
 /* classList polyfill by Eli Grey
  * (http://purl.eligrey.com/github/classList.js/blob/master/classList.js) */
@@ -201,29 +205,122 @@ function updateSlides() {
   enableSlideFrames(curSlide - 1);
   enableSlideFrames(curSlide + 2);
 
+  updateSteps();
   updateHash();
 };
 
 function prevSlide() {
   hideHelpText();
-  if (curSlide > 0) {
+  if (curStep > 0) {
+    curStep--;
+    updateSteps();
+  } else if (curSlide > 0) {
     curSlide--;
+    curStep = getStepCount(curSlide);
 
     updateSlides();
   }
 
-  if (notesEnabled) localStorage.setItem('destSlide', curSlide);
+  saveSlide();
 };
 
 function nextSlide() {
   hideHelpText();
-  if (curSlide < slideEls.length - 1) {
+  if (curStep < getStepCount(curSlide)) {
+    curStep++;
+    updateSteps();
+  } else if (curSlide < slideEls.length - 1) {
     curSlide++;
+    curStep = 0;
 
     updateSlides();
   }
 
-  if (notesEnabled) localStorage.setItem('destSlide', curSlide);
+  saveSlide();
+};
+
+// saveSlide stores the current slide and step for the other window of
+// presenter notes
+function saveSlide() {
+  if (!notesEnabled) return;
+  localStorage.setItem('destStep', curStep);
+  localStorage.setItem('destSlide', curSlide);
+};
+
+/* Code steps */
+
+// code blocks rendered with `.code -steps` have the number of steps in
+// `data-steps`, their lines have the steps they are highlighted in,
+// commands of terminal sessions have the step they are revealed in
+function getStepCount(no) {
+  var el = getSlideEl(no);
+  if (!el) {
+    return 0;
+  }
+
+  var count = 0;
+  var blocks = el.querySelectorAll('pre[data-steps]');
+  for (var i = 0, block; block = blocks[i]; i++) {
+    count = Math.max(count, parseInt(block.getAttribute('data-steps')) || 0);
+  }
+
+  return count;
+};
+
+function updateSteps() {
+  var el = getSlideEl(curSlide);
+  if (!el) {
+    return;
+  }
+
+  var lines = el.querySelectorAll('pre[data-steps] > span[data-steps]');
+  for (var i = 0, line; line = lines[i]; i++) {
+    var steps = line.getAttribute('data-steps').split(' ');
+    if (steps.indexOf(String(curStep)) !== -1) {
+      line.classList.add('step-current');
+    } else {
+      line.classList.remove('step-current');
+    }
+  }
+
+  var commands = el.querySelectorAll('pre[data-steps] > span[data-reveal]');
+  for (var i = 0, command; command = commands[i]; i++) {
+    if (parseInt(command.getAttribute('data-reveal')) > curStep) {
+      command.classList.add('step-hidden');
+    } else {
+      command.classList.remove('step-hidden');
+    }
+  }
+};
+
+/* Code callouts */
+
+// hovering a callout of a code block highlights its badge and annotation
+function setupCallouts() {
+  var blocks = document.querySelectorAll('div.code');
+  for (var i = 0, block; block = blocks[i]; i++) {
+    if (block.querySelector('ol.callouts')) {
+      block.addEventListener('mouseover', highlightCallout, false);
+      block.addEventListener('mouseout', highlightCallout, false);
+    }
+  }
+};
+
+function highlightCallout(event) {
+  var callout = event.target.closest('[data-callout]');
+  if (!callout) {
+    return;
+  }
+
+  var n = callout.getAttribute('data-callout');
+  var els = this.querySelectorAll('[data-callout="' + n + '"]');
+  for (var i = 0, el; el = els[i]; i++) {
+    if (event.type == 'mouseover') {
+      el.classList.add('callout-active');
+    } else {
+      el.classList.remove('callout-active');
+    }
+  }
 };
 
 /* Slide events */
@@ -515,6 +612,8 @@ function handleDomLoaded() {
 
   setupInteraction();
 
+  setupCallouts();
+
   if (window.location.hostname == 'localhost' || window.location.hostname == '127.0.0.1' || window.location.hostname == '::1') {
     hideHelpText();
   }
@@ -588,6 +687,7 @@ function setupNotesSync() {
 
   setupPlayCodeSync();
   setupPlayResizeSync();
+  localStorage.setItem('destStep', curStep);
   localStorage.setItem('destSlide', curSlide);
   window.addEventListener('storage', updateOtherWindow, false);
 }
@@ -599,12 +699,18 @@ function updateOtherWindow(e) {
   var isRemoveStorageEvent = !e.newValue;
   if (isRemoveStorageEvent) return;
 
-  var destSlide = localStorage.getItem('destSlide');
-  while (destSlide > curSlide) {
-    nextSlide();
-  }
-  while (destSlide < curSlide) {
-    prevSlide();
+  // Steps of `-hl` and `-steps` blocks are synced as well, the position
+  // is set directly so this window doesn't store it back
+  var destSlide = parseInt(localStorage.getItem('destSlide'), 10);
+  var destStep = parseInt(localStorage.getItem('destStep'), 10) || 0;
+
+  if (!isNaN(destSlide) && destSlide != curSlide) {
+    curSlide = destSlide;
+    curStep = destStep;
+    updateSlides();
+  } else if (destSlide == curSlide && destStep != curStep) {
+    curStep = destStep;
+    updateSteps();
   }
 
   updatePlay(e);